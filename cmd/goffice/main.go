@@ -1,21 +1,55 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"goffice/internal/docx"
 	"goffice/internal/parser"
 )
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("用法: ./程序名 输入文件.md 输出文件.docx")
+	titleBlock := flag.Bool("title-block", true, "在文档开头生成元数据中的标题、作者、日期和摘要，已有标题页时用 -title-block=false 关闭")
+	toc := flag.Bool("toc", false, "在正文开头插入目录")
+	endnotes := flag.Bool("endnotes", false, "将脚注作为尾注放在文档末尾")
+	numberSections := flag.Bool("number-sections", false, "为标题添加多级编号")
+	referenceDoc := flag.String("reference-doc", "", "复用其样式、页眉页脚和页面设置的参考DOCX文件")
+	preset := flag.String("preset", "", "版式预设，gongwen 为GB/T 9704公文格式")
+	pageSize := flag.String("page-size", "", "纸张大小：A3、A4、A5、B5、Letter、Legal 或 宽x高，如 184x260mm")
+	orientation := flag.String("orientation", "", "纸张方向：portrait 或 landscape")
+	margins := flag.String("margins", "", "页边距：一个值用于四边，或 上下,左右，或 上,右,下,左，如 25mm 或 1in")
+	gutter := flag.String("gutter", "", "装订线宽度")
+	headerDistance := flag.String("header-distance", "", "页眉距纸张上边缘的距离")
+	footerDistance := flag.String("footer-distance", "", "页脚距纸张下边缘的距离")
+	header := flag.String("header", "", "页眉模板，可用 {title}、{author}、{date}、{page}、{pages}，以 | 分隔左、中、右三部分")
+	footer := flag.String("footer", "", "页脚模板，如 \"第{page}页，共{pages}页\"")
+	firstHeader := flag.String("first-header", "", "首页的页眉模板，设置后首页不同")
+	firstFooter := flag.String("first-footer", "", "首页的页脚模板，设置后首页不同")
+	evenHeader := flag.String("even-header", "", "偶数页的页眉模板，设置后奇偶页不同")
+	evenFooter := flag.String("even-footer", "", "偶数页的页脚模板，设置后奇偶页不同")
+	columns := flag.Int("columns", 0, "正文的栏数，标题信息和目录不分栏，覆盖元数据中的 columns")
+	columnGap := flag.String("column-gap", "", "栏间距，如 8mm，覆盖元数据中的 column-gap")
+	commentAuthor := flag.String("comment-author", "", "批注的作者，默认为 Reviewer")
+	commentDate := flag.String("comment-date", "", "批注的日期，如 2024-05-01 或 2024-05-01T14:30:00，默认为当前时间")
+	differentFirst := flag.Bool("different-first-page", false, "首页不同，未设置首页模板时首页没有页眉页脚")
+	lang := flag.String("lang", "", "文档语言，如 zh-CN、en-US，覆盖元数据中的 lang")
+	latinFont := flag.String("latin-font", "", "正文的西文字体，默认为 Times New Roman")
+	cjkFont := flag.String("cjk-font", "", "正文的中文字体，默认为宋体")
+	headingFont := flag.String("heading-cjk-font", "", "标题的中文字体，默认为黑体")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "用法: ./程序名 [选项] 输入文件.md 输出文件.docx")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
 		return
 	}
 
-	inputFile := os.Args[1]
-	outputFile := os.Args[2]
+	inputFile := flag.Arg(0)
+	outputFile := flag.Arg(1)
 
 	mdContent, err := os.ReadFile(inputFile)
 	if err != nil {
@@ -23,8 +57,44 @@ func main() {
 		return
 	}
 
-	doc := parser.ParseMarkdown(string(mdContent))
-	err = docx.CreateDOCX(doc, outputFile)
+	// 警告不影响转换，输出到标准错误以免与转换结果混在一起
+	warn := func(message string) {
+		fmt.Fprintln(os.Stderr, "警告:", message)
+	}
+	doc, warnings := parser.ParseMarkdownWithWarnings(string(mdContent))
+	for _, message := range warnings {
+		warn(message)
+	}
+	err = docx.CreateDOCXWithOptions(doc, outputFile, docx.Options{
+		ResourceDir: filepath.Dir(inputFile),
+		TitleBlock:  *titleBlock,
+		TOC:         *toc,
+		Endnotes:    *endnotes,
+
+		NumberHeadings: *numberSections,
+		ReferenceDoc:   *referenceDoc,
+
+		Preset: *preset,
+		Fonts:  docx.Fonts{Latin: *latinFont, EastAsian: *cjkFont, HeadingEastAsian: *headingFont},
+		Lang:   *lang,
+		Page: docx.PageSetup{
+			Size: *pageSize, Orientation: *orientation, Margins: *margins,
+			Gutter: *gutter, Header: *headerDistance, Footer: *footerDistance,
+		},
+		HeaderFooter: docx.HeaderFooter{
+			Header: *header, Footer: *footer,
+			FirstHeader: *firstHeader, FirstFooter: *firstFooter,
+			EvenHeader: *evenHeader, EvenFooter: *evenFooter,
+			DifferentFirst: *differentFirst,
+		},
+		Columns:   *columns,
+		ColumnGap: *columnGap,
+
+		CommentAuthor: *commentAuthor,
+		CommentDate:   *commentDate,
+
+		Warn: warn,
+	})
 	if err != nil {
 		fmt.Println("错误:", err)
 	} else {
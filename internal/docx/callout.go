@@ -0,0 +1,107 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// CalloutStyle 定义一种提示块的外观
+type CalloutStyle struct {
+	Title string // 默认标题
+	Icon  string // 标题前的图标字符
+	Color string // 边框和标题的颜色，十六进制RGB，如 0969DA
+	Fill  string // 底纹颜色，十六进制RGB
+}
+
+// defaultCalloutStyles 内置的提示块外观，与GitHub的五种提示类型对应
+var defaultCalloutStyles = map[string]CalloutStyle{
+	"note":      {Title: "Note", Icon: "ℹ", Color: "0969DA", Fill: "EAF3FD"},
+	"tip":       {Title: "Tip", Icon: "✔", Color: "1A7F37", Fill: "EAF7EE"},
+	"important": {Title: "Important", Icon: "❢", Color: "8250DF", Fill: "F4EFFC"},
+	"warning":   {Title: "Warning", Icon: "⚠", Color: "9A6700", Fill: "FFF8E1"},
+	"caution":   {Title: "Caution", Icon: "⛔", Color: "CF222E", Fill: "FDEDEE"},
+}
+
+// calloutAliases Obsidian等工具中常见的提示类型别名
+var calloutAliases = map[string]string{
+	"info":      "note",
+	"abstract":  "note",
+	"hint":      "tip",
+	"success":   "tip",
+	"attention": "warning",
+	"danger":    "caution",
+	"error":     "caution",
+}
+
+// calloutStyle 返回提示类型对应的外观，选项中的设置覆盖内置设置，未知类型使用note的外观，类型为空时标题也与note相同
+func (o Options) calloutStyle(kind string) CalloutStyle {
+	base, ok := defaultCalloutStyles[kind]
+	if !ok {
+		if alias, found := calloutAliases[kind]; found {
+			base = defaultCalloutStyles[alias]
+		} else {
+			base = defaultCalloutStyles["note"]
+		}
+		if len(kind) > 0 {
+			base.Title = strings.ToUpper(kind[:1]) + kind[1:]
+		}
+	}
+	if custom, ok := o.Callouts[kind]; ok {
+		if custom.Title != "" {
+			base.Title = custom.Title
+		}
+		if custom.Icon != "" {
+			base.Icon = custom.Icon
+		}
+		if custom.Color != "" {
+			base.Color = custom.Color
+		}
+		if custom.Fill != "" {
+			base.Fill = custom.Fill
+		}
+	}
+	return base
+}
+
+// calloutXML 将提示块生成为带底纹和边框的单元格表格，左边框加粗
+func (g *generator) calloutXML(c models.Callout) string {
+	style := g.opts.calloutStyle(c.Kind)
+	border := func(side string, size int) string {
+		return fmt.Sprintf(`<w:%s w:val="single" w:sz="%d" w:space="0" w:color="%s"/>`, side, size, style.Color)
+	}
+	shd := `<w:shd w:val="clear" w:color="auto" w:fill="` + style.Fill + `"/>`
+
+	textWidth := g.textWidth()
+	xml := `<w:tbl><w:tblPr>` + fmt.Sprintf(`<w:tblW w:w="%d" w:type="dxa"/>`, textWidth) +
+		`<w:tblBorders>` + border("top", 4) + border("left", 24) + border("bottom", 4) + border("right", 4) + `</w:tblBorders>` +
+		shd + `<w:tblLayout w:type="fixed"/>` +
+		`<w:tblCellMar><w:top w:w="80" w:type="dxa"/><w:left w:w="160" w:type="dxa"/><w:bottom w:w="80" w:type="dxa"/><w:right w:w="160" w:type="dxa"/></w:tblCellMar>` +
+		`</w:tblPr>` + fmt.Sprintf(`<w:tblGrid><w:gridCol w:w="%d"/></w:tblGrid>`, textWidth) +
+		`<w:tr><w:tc><w:tcPr>` + fmt.Sprintf(`<w:tcW w:w="%d" w:type="dxa"/>`, textWidth) + shd + `</w:tcPr>`
+
+	// 标题段落：图标和标题使用提示块的颜色
+	titleRPr := runProps{bold: true, color: style.Color}
+	xml += `<w:p><w:pPr><w:keepNext/><w:spacing w:after="60"/></w:pPr>`
+	if style.Icon != "" {
+		iconRPr := titleRPr
+		iconRPr.fonts = fonts{ascii: "Segoe UI Symbol", hAnsi: "Segoe UI Symbol", eastAsia: "Segoe UI Symbol"}
+		xml += `<w:r><w:rPr>` + iconRPr.XML() + `</w:rPr><w:t xml:space="preserve">` + escapeXML(style.Icon) + ` </w:t></w:r>`
+	}
+	if len(c.Title) > 0 {
+		xml += g.runsXML(c.Title, titleRPr)
+	} else {
+		xml += `<w:r><w:rPr>` + titleRPr.XML() + `</w:rPr><w:t>` + escapeXML(style.Title) + `</w:t></w:r>`
+	}
+	xml += `</w:p>`
+
+	for _, block := range c.Blocks {
+		xml += g.blockXML(block)
+	}
+	// 单元格必须以段落结束
+	if !strings.HasSuffix(xml, `</w:p>`) {
+		xml += `<w:p/>`
+	}
+	return xml + `</w:tc></w:tr></w:tbl>`
+}
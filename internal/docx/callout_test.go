@@ -0,0 +1,62 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestCalloutXML(t *testing.T) {
+	callout := models.Callout{
+		Kind:   "warning",
+		Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "请先备份"}}}},
+	}
+
+	t.Run("内置外观", func(t *testing.T) {
+		xml := newGenerator(Options{}).blockXML(callout)
+		if !strings.Contains(xml, `<w:left w:val="single" w:sz="24" w:space="0" w:color="9A6700"/>`) {
+			t.Errorf("提示块应带有类型颜色的加粗左边框，实际XML为:\n%s", xml)
+		}
+		if strings.Count(xml, `w:fill="FFF8E1"`) != 2 {
+			t.Error("表格和单元格都应设置底纹")
+		}
+		if !strings.Contains(xml, `<w:t xml:space="preserve">⚠ </w:t>`) || !strings.Contains(xml, `<w:t>Warning</w:t>`) {
+			t.Error("提示块应包含图标和默认标题")
+		}
+		if !strings.Contains(xml, `<w:t>请先备份</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`) {
+			t.Error("提示块内容应位于单元格内")
+		}
+	})
+
+	t.Run("按类型自定义外观", func(t *testing.T) {
+		g := newGenerator(Options{Callouts: map[string]CalloutStyle{"warning": {Title: "警告", Color: "FF0000"}}})
+		xml := g.blockXML(callout)
+		if !strings.Contains(xml, `<w:t>警告</w:t>`) || !strings.Contains(xml, `w:color="FF0000"`) {
+			t.Errorf("自定义的标题和颜色应生效，实际XML为:\n%s", xml)
+		}
+		if !strings.Contains(xml, `w:fill="FFF8E1"`) {
+			t.Error("未自定义的属性应保留内置设置")
+		}
+	})
+
+	t.Run("自定义标题和未知类型", func(t *testing.T) {
+		xml := newGenerator(Options{}).blockXML(models.Callout{Kind: "danger", Title: []models.Inline{models.Text{Content: "危险"}}})
+		if !strings.Contains(xml, `w:color="CF222E"`) || !strings.Contains(xml, `<w:t>危险</w:t>`) {
+			t.Errorf("别名类型应使用对应的外观，实际XML为:\n%s", xml)
+		}
+		if style := (Options{}).calloutStyle("abstract"); style.Title != "Abstract" {
+			t.Errorf("未内置的类型应以类型名作为标题，实际为'%s'", style.Title)
+		}
+		if style := (Options{}).calloutStyle(""); style.Title != "Note" {
+			t.Errorf("类型为空时应使用note的标题，实际为'%s'", style.Title)
+		}
+	})
+
+	t.Run("相邻提示块之间插入段落", func(t *testing.T) {
+		xml := GenerateDocumentXML(models.Document{Blocks: []models.Block{callout, callout}})
+		if !strings.Contains(xml, `</w:tbl><w:p/><w:tbl>`) {
+			t.Error("相邻的表格之间应插入空段落")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"goffice/internal/models"
+)
+
+// 批注部件的关系类型和内容类型
+const (
+	relTypeComments     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	contentTypeComments = "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"
+)
+
+// commentDateLayouts 批注日期选项可用的格式
+var commentDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseCommentDate 解析批注日期，为空时返回 now
+func parseCommentDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return now, nil
+	}
+	for _, layout := range commentDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无效的批注日期: %s，应为 2006-01-02 或 2006-01-02T15:04:05 形式", s)
+}
+
+// commentAuthor 返回批注的作者
+func (o Options) commentAuthor() string {
+	if author := strings.TrimSpace(o.CommentAuthor); author != "" {
+		return author
+	}
+	return "Reviewer"
+}
+
+// initials 取作者姓名中每个词的首字母，中文姓名取第一个字
+func initials(name string) string {
+	var s string
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			s += string(unicode.ToUpper(r))
+			break
+		}
+	}
+	return s
+}
+
+// commentXML 生成正文中的批注：被批注的文字置于批注范围之间，范围之后是批注引用。
+// 没有批注内容时只突出显示文字；批注内容中的批注不被Word支持，只输出被批注的文字
+func (g *generator) commentXML(c models.Comment, rPr runProps) string {
+	if g.inComment {
+		return g.runsXML(c.Content, rPr)
+	}
+	if len(c.Note) == 0 {
+		rPr.highlight = "yellow"
+		return g.runsXML(c.Content, rPr)
+	}
+	id := len(g.comments)
+	g.comments = append(g.comments, c.Note)
+	return fmt.Sprintf(`<w:commentRangeStart w:id="%d"/>`, id) + g.runsXML(c.Content, rPr) +
+		fmt.Sprintf(`<w:commentRangeEnd w:id="%d"/><w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="%d"/></w:r>`, id, id)
+}
+
+// commentsXML 生成comments.xml，批注的作者和日期来自选项
+func (g *generator) commentsXML() string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:comments
+    xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+    xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"
+    xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+    xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing">`
+
+	g.inComment = true
+	defer func() { g.inComment = false }()
+	author := g.opts.commentAuthor()
+	// Word按本地时间显示批注日期，不做时区换算
+	date := g.commentDate.Format("2006-01-02T15:04:05Z")
+	mark := `<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:annotationRef/></w:r>`
+	for id, note := range g.comments {
+		xml += fmt.Sprintf(`<w:comment w:id="%d" w:author="%s" w:date="%s" w:initials="%s">`, id, escapeXML(author), date, escapeXML(initials(author)))
+		xml += `<w:p><w:pPr><w:pStyle w:val="CommentText"/></w:pPr>` + mark + g.runsXML(note, runProps{}) + `</w:p></w:comment>`
+	}
+	return xml + `</w:comments>`
+}
@@ -0,0 +1,103 @@
+package docx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestComments(t *testing.T) {
+	text := func(s string) []models.Inline {
+		return []models.Inline{models.Text{Content: s}}
+	}
+
+	t.Run("批注范围和引用", func(t *testing.T) {
+		g := newGenerator(Options{})
+		xml := g.runsXML([]models.Inline{
+			models.Bold{Content: []models.Inline{models.Comment{Content: text("表述不清"), Note: text("建议改写")}}},
+			models.Comment{Note: text("补充数据")},
+			models.Comment{Content: text("重点")},
+		}, runProps{})
+		want := `<w:commentRangeStart w:id="0"/><w:r><w:rPr><w:b/><w:bCs/></w:rPr><w:t>表述不清</w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+			`<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="0"/></w:r>` +
+			`<w:commentRangeStart w:id="1"/><w:commentRangeEnd w:id="1"/><w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="1"/></w:r>` +
+			`<w:r><w:rPr><w:highlight w:val="yellow"/></w:rPr><w:t>重点</w:t></w:r>`
+		if xml != want {
+			t.Errorf("批注错误:\n%s\n应为:\n%s", xml, want)
+		}
+		if len(g.comments) != 2 {
+			t.Errorf("应记录2条批注，实际为%d", len(g.comments))
+		}
+	})
+
+	t.Run("作者和日期", func(t *testing.T) {
+		g := newGenerator(Options{CommentAuthor: "Ada Lovelace", CommentDate: "2024-05-01 14:30"})
+		g.comments = [][]models.Inline{
+			{models.Text{Content: "见"}, models.FootnoteReference{ID: "inline 1"}, models.Comment{Content: text("嵌套"), Note: text("忽略")}},
+		}
+		g.footnotes = map[string][]models.Block{"inline 1": {models.Paragraph{Inlines: text("行内脚注")}}}
+		xml := g.commentsXML()
+		want := `<w:comment w:id="0" w:author="Ada Lovelace" w:date="2024-05-01T14:30:00Z" w:initials="AL">` +
+			`<w:p><w:pPr><w:pStyle w:val="CommentText"/></w:pPr><w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:annotationRef/></w:r>` +
+			`<w:r><w:t>见</w:t></w:r><w:r><w:t>(行内脚注)</w:t></w:r><w:r><w:t>嵌套</w:t></w:r></w:p></w:comment>`
+		if !strings.Contains(xml, want) {
+			t.Errorf("批注内容错误:\n%s", xml)
+		}
+		if err := wellFormed(xml); err != nil {
+			t.Error(err)
+		}
+		if len(g.notes) != 0 || len(g.comments) != 1 {
+			t.Error("批注内容中的脚注和批注不应生成")
+		}
+
+		g = newGenerator(Options{})
+		if g.opts.commentAuthor() != "Reviewer" || !g.commentDate.Equal(g.now) {
+			t.Error("默认的作者和日期错误")
+		}
+		if got := initials("张三"); got != "张" {
+			t.Errorf("中文姓名的缩写错误: %s", got)
+		}
+	})
+
+	t.Run("无效的日期", func(t *testing.T) {
+		err := CreateDOCXWithOptions(models.Document{}, filepath.Join(t.TempDir(), "x.docx"), Options{CommentDate: "昨天"})
+		if err == nil {
+			t.Error("无效的批注日期应返回错误")
+		}
+	})
+
+	t.Run("批注部件", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.docx")
+		doc := models.Document{
+			Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{
+				models.Comment{Content: text("正文"), Note: text("正文的批注")},
+				models.FootnoteReference{ID: "n"},
+			}}},
+			Footnotes: []models.Footnote{{ID: "n", Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Comment{Content: text("脚注"), Note: text("脚注的批注")}}}}}},
+		}
+		if err := CreateDOCXWithOptions(doc, out, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		files := readDOCX(t, out)
+		comments := files["word/comments.xml"]
+		if !strings.Contains(comments, "正文的批注") || !strings.Contains(comments, `w:id="1"`) || !strings.Contains(comments, "脚注的批注") {
+			t.Errorf("应包含正文和脚注中的批注:\n%s", comments)
+		}
+		if !strings.Contains(files["word/_rels/document.xml.rels"], `Type="`+relTypeComments+`" Target="comments.xml"`) ||
+			!strings.Contains(files["[Content_Types].xml"], `<Override PartName="/word/comments.xml" ContentType="`+contentTypeComments+`"/>`) {
+			t.Error("缺少批注部件的关系或内容类型")
+		}
+		if !strings.Contains(files["word/styles.xml"], `w:styleId="CommentText"`) || !strings.Contains(files["word/styles.xml"], `w:styleId="CommentReference"`) {
+			t.Error("缺少批注样式")
+		}
+
+		if err := CreateDOCXWithOptions(models.Document{}, out, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := readDOCX(t, out)["word/comments.xml"]; ok {
+			t.Error("没有批注时不应生成comments.xml")
+		}
+	})
+}
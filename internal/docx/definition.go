@@ -0,0 +1,23 @@
+package docx
+
+import (
+	"goffice/internal/models"
+)
+
+// definitionListXML 生成定义列表：术语使用 Definition Term 样式，定义中的段落使用缩进的 Definition 样式
+func (g *generator) definitionListXML(list models.DefinitionList) string {
+	xml := ""
+	for _, item := range list.Items {
+		xml += `<w:p><w:pPr><w:pStyle w:val="DefinitionTerm"/></w:pPr>` + g.inlinesXML(item.Term) + `</w:p>`
+		for _, definition := range item.Definitions {
+			for _, block := range definition {
+				if p, ok := block.(models.Paragraph); ok {
+					xml += g.paragraphXML(p, `<w:pStyle w:val="Definition"/>`)
+				} else {
+					xml += g.blockXML(block)
+				}
+			}
+		}
+	}
+	return xml
+}
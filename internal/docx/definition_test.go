@@ -0,0 +1,29 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestDefinitionListXML(t *testing.T) {
+	list := models.DefinitionList{Items: []models.DefinitionItem{{
+		Term: []models.Inline{models.Text{Content: "API"}},
+		Definitions: [][]models.Block{
+			{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "应用程序接口"}}}},
+			{models.Math{LaTeX: "x", Display: true}},
+		},
+	}}}
+	xml := newGenerator(Options{}).blockXML(list)
+
+	if !strings.HasPrefix(xml, `<w:p><w:pPr><w:pStyle w:val="DefinitionTerm"/></w:pPr><w:r><w:t>API</w:t></w:r></w:p>`) {
+		t.Errorf("术语段落生成错误，实际为%s", xml)
+	}
+	if !strings.Contains(xml, `<w:pStyle w:val="Definition"/><w:rPr></w:rPr></w:pPr><w:r><w:t>应用程序接口</w:t></w:r>`) {
+		t.Errorf("定义段落应使用Definition样式，实际为%s", xml)
+	}
+	if !strings.Contains(xml, `<m:oMathPara>`) {
+		t.Error("定义中的其他块元素应正常生成")
+	}
+}
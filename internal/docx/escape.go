@@ -0,0 +1,64 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// escapeXML 转义XML特殊字符并去掉XML 1.0中不允许出现的字符，结果可用于属性值和文本
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(strings.Map(validXMLChar, s)))
+	return b.String()
+}
+
+// validXMLChar 保留XML 1.0允许的字符，其他字符（如大多数控制字符）返回-1被去掉
+func validXMLChar(r rune) rune {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return r
+	case r >= 0x20 && r <= 0xD7FF, r >= 0xE000 && r <= 0xFFFD, r >= 0x10000 && r <= 0x10FFFF:
+		return r
+	}
+	return -1
+}
+
+// textXML 生成转义后的 <w:t> 元素，文本首尾有空白或含连续空格时加上 xml:space="preserve" 以免被Word去掉
+func textXML(text string) string {
+	if strings.TrimSpace(text) != text || strings.Contains(text, "  ") {
+		return `<w:t xml:space="preserve">` + escapeXML(text) + `</w:t>`
+	}
+	return `<w:t>` + escapeXML(text) + `</w:t>`
+}
+
+// textRunXML 生成带格式的文本段，没有格式时省略 <w:rPr>。
+// Word按字符自动选用西文或东亚字体，只有含共用标点的中文需要单独成段并指明使用东亚字体
+func textRunXML(rPr runProps, text string) string {
+	segments := splitScripts(text)
+	split := false
+	for _, seg := range segments {
+		split = split || seg.eastAsia && seg.shared
+	}
+	if !split {
+		return runXML(rPr, text)
+	}
+	xml := ""
+	for _, seg := range segments {
+		if seg.eastAsia {
+			xml += runXML(eastAsiaHint(rPr), seg.text)
+		} else {
+			xml += runXML(rPr, seg.text)
+		}
+	}
+	return xml
+}
+
+// runXML 生成一个文本段
+func runXML(rPr runProps, text string) string {
+	props := rPr.XML()
+	if props == "" {
+		return `<w:r>` + textXML(text) + `</w:r>`
+	}
+	return `<w:r><w:rPr>` + props + `</w:rPr>` + textXML(text) + `</w:r>`
+}
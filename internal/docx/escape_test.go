@@ -0,0 +1,97 @@
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+	"goffice/internal/parser"
+)
+
+func TestEscapeXML(t *testing.T) {
+	t.Run("特殊字符", func(t *testing.T) {
+		if got := escapeXML(`a < b & "c"`); got != `a &lt; b &amp; &#34;c&#34;` {
+			t.Errorf("转义结果错误: %s", got)
+		}
+	})
+
+	t.Run("去掉控制字符", func(t *testing.T) {
+		if got := escapeXML("a\x00b\x1bc￾d"); got != "abcd" {
+			t.Errorf("控制字符未被去掉: %q", got)
+		}
+	})
+
+	t.Run("保留空白", func(t *testing.T) {
+		if got := textXML(" 前导空格"); got != `<w:t xml:space="preserve"> 前导空格</w:t>` {
+			t.Errorf("首尾空白未保留: %s", got)
+		}
+		if got := textXML("文本"); got != `<w:t>文本</w:t>` {
+			t.Errorf("普通文本不应加 xml:space: %s", got)
+		}
+	})
+
+	t.Run("文本和公式", func(t *testing.T) {
+		doc := models.Document{Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{
+				models.Text{Content: "1 < 2 & 3"},
+				models.Bold{Content: []models.Inline{models.Text{Content: "<b>"}}},
+				models.Math{LaTeX: "a < b"},
+			}},
+		}}
+		xml := GenerateDocumentXML(doc)
+		for _, want := range []string{`<w:t>1 &lt; 2 &amp; 3</w:t>`, `<w:b/><w:bCs/></w:rPr><w:t>&lt;b&gt;</w:t>`, `<m:t>a &lt; b</m:t>`} {
+			if !strings.Contains(xml, want) {
+				t.Errorf("缺少 %s，实际XML为:\n%s", want, xml)
+			}
+		}
+		if err := wellFormed(xml); err != nil {
+			t.Errorf("document.xml 格式不正确: %v", err)
+		}
+	})
+}
+
+// FuzzDocumentXML 检查任意Markdown生成的document.xml都是格式良好的XML
+func FuzzDocumentXML(f *testing.F) {
+	for _, seed := range []string{
+		"# 标题 <tag> & more\n\n正文 a < b && c > d",
+		"公式 $a < b$ 和 $$\\frac{x<1}{y&2}$$",
+		"**粗体 <i>** `code <x>` [链接 & 文本](http://a.com/?a=1&b=2 \"标题 <t>\")",
+		"控制字符\x00\x01\x0b\x1f 结束",
+		"| a<b | c&d |\n|---|---|\n| 1 | 2 |",
+		"脚注[^1]\n\n[^1]: 注释 <内容> & 更多",
+		"---\ntitle: A & B <C>\nauthor: \"x < y\"\n---\n\n正文",
+		"术语 <x>\n: 定义 & 说明",
+		"![图 <1>](missing.png \"t&t\")",
+		"<b>粗</b> <a href=\"x?a&b\">链接</a> <sub>&lt;</sub>",
+		"`<w:r/>`{=openxml} 文本",
+		"<table><tr><th>a<th>b<tr><td>c<td>d</table>",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, md string) {
+		doc := parser.ParseMarkdown(md)
+		g := newGenerator(Options{TitleBlock: true, TOC: true, NumberHeadings: true})
+		xml := g.documentXML(doc)
+		if g.err != nil {
+			// 格式错误的原始OpenXML内容会被拒绝，不生成文件
+			return
+		}
+		if err := wellFormed(xml); err != nil {
+			t.Fatalf("document.xml 格式不正确: %v\n输入: %q\n%s", err, md, xml)
+		}
+	})
+}
+
+// wellFormed 用encoding/xml完整解析文档，检查其是否格式良好
+func wellFormed(document string) error {
+	d := xml.NewDecoder(strings.NewReader(document))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package docx
+
+import (
+	"fmt"
+	"unicode"
+
+	"goffice/internal/models"
+)
+
+// figureEntry 记录图的编号和题注，用于交叉引用和图目录
+type figureEntry struct {
+	id       string // 图的标识符，未指定时为空
+	number   int    // 图的编号，从1开始
+	caption  string // 题注的纯文本
+	bookmark string // 题注编号处的书签名
+}
+
+// collectFigures 预先为文档中的图编号，使前向引用也能得到正确的编号。
+// 图的书签与标题的书签共用一个命名空间，书签名记入used，之后生成的标题书签不会与之重名
+func (g *generator) collectFigures(blocks []models.Block, used map[string]bool) {
+	g.figures, g.figuresDone = nil, 0
+	walkBlocks(blocks, func(block models.Block) {
+		if f, ok := block.(models.Figure); ok {
+			n := len(g.figures) + 1
+			entry := figureEntry{id: f.Image.Attr.ID, number: n, caption: models.PlainText(f.Caption)}
+			if entry.id != "" {
+				entry.bookmark = uniqueBookmark(bookmarkName(entry.id), used)
+			} else {
+				entry.bookmark = uniqueBookmark(fmt.Sprintf("_Figure%d", n), used)
+			}
+			g.figures = append(g.figures, entry)
+		}
+	})
+}
+
+// figureXML 生成图片段落和带SEQ域编号的题注段落
+func (g *generator) figureXML(f models.Figure) string {
+	entry := g.nextFigure()
+	xml := `<w:p><w:pPr><w:keepNext/><w:jc w:val="center"/></w:pPr>` + g.imageXML(f.Image) + `</w:p>`
+	xml += `<w:p><w:pPr><w:pStyle w:val="Caption"/></w:pPr>`
+	xml += g.bookmarkXML(entry.bookmark, fmt.Sprintf(`<w:r><w:t xml:space="preserve">%s </w:t></w:r>`, escapeXML(g.opts.figureLabel()))+
+		fmt.Sprintf(`<w:fldSimple w:instr=" SEQ Figure \* ARABIC "><w:r><w:t>%d</w:t></w:r></w:fldSimple>`, entry.number))
+	if len(f.Caption) > 0 {
+		xml += `<w:r><w:t xml:space="preserve">: </w:t></w:r>` + g.inlinesXML(f.Caption)
+	}
+	return xml + `</w:p>`
+}
+
+// nextFigure 返回下一个待生成的图的编号信息
+func (g *generator) nextFigure() figureEntry {
+	if g.figuresDone >= len(g.figures) {
+		// 未经预扫描的图（如直接调用blockXML时）按出现顺序编号
+		n := len(g.figures) + 1
+		g.figures = append(g.figures, figureEntry{number: n, bookmark: fmt.Sprintf("_Figure%d", n)})
+	}
+	entry := g.figures[g.figuresDone]
+	g.figuresDone++
+	return entry
+}
+
+// referenceXML 生成指向图题注编号的REF域
+func (g *generator) referenceXML(r models.Reference) string {
+	for _, entry := range g.figures {
+		if entry.id == r.ID {
+			return fmt.Sprintf(`<w:fldSimple w:instr=" REF %s \h "><w:r><w:t xml:space="preserve">%s %d</w:t></w:r></w:fldSimple>`,
+				entry.bookmark, escapeXML(g.opts.figureLabel()), entry.number)
+		}
+	}
+	g.warn("未找到被引用的图: @%s", r.ID)
+	return `<w:r><w:t>@` + escapeXML(r.ID) + `</w:t></w:r>`
+}
+
+// listOfFiguresXML 生成图目录，即带 \c "Figure" 开关的TOC域，并预先填入各图的题注
+func (g *generator) listOfFiguresXML() string {
+	var entries []fieldParagraph
+	for _, entry := range g.figures {
+		text := fmt.Sprintf("%s %d", g.opts.figureLabel(), entry.number)
+		if entry.caption != "" {
+			text += ": " + entry.caption
+		}
+		entries = append(entries, fieldParagraph{style: "TableofFigures", content: fieldEntryXML(entry.bookmark, text)})
+	}
+	if len(entries) == 0 {
+		entries = []fieldParagraph{{style: "TableofFigures"}}
+	}
+	return g.complexFieldXML(`TOC \h \z \c "Figure"`, entries)
+}
+
+// bookmarkXML 用书签包裹内容
+func (g *generator) bookmarkXML(name, content string) string {
+	id := g.bookmarks
+	g.bookmarks++
+	return fmt.Sprintf(`<w:bookmarkStart w:id="%d" w:name="%s"/>%s<w:bookmarkEnd w:id="%d"/>`, id, name, content, id)
+}
+
+// uniqueBookmark 返回未被使用的书签名并将其记入used，重名时依次追加 _1、_2，总长度仍不超过40个字符
+func uniqueBookmark(name string, used map[string]bool) string {
+	bookmark := name
+	for i := 1; used[bookmark]; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		base := []rune(name)
+		if len(base)+len(suffix) > 40 {
+			base = base[:40-len(suffix)]
+		}
+		bookmark = string(base) + suffix
+	}
+	used[bookmark] = true
+	return bookmark
+}
+
+// bookmarkName 将标识符转换为合法的书签名：只含字母、数字和下划线，以字母开头，不超过40个字符
+func bookmarkName(id string) string {
+	var runes []rune
+	for _, r := range id {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		} else {
+			runes = append(runes, '_')
+		}
+	}
+	if len(runes) == 0 || !unicode.IsLetter(runes[0]) {
+		runes = append([]rune("x"), runes...)
+	}
+	if len(runes) > 40 {
+		runes = runes[:40]
+	}
+	return string(runes)
+}
@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestFigureXML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPNG(t, dir, "arch.png", 20, 10)
+	doc := models.Document{Blocks: []models.Block{
+		models.ListOfFigures{},
+		models.Paragraph{Inlines: []models.Inline{
+			models.Text{Content: "如"},
+			models.Reference{ID: "fig:arch"},
+			models.Text{Content: "所示"},
+		}},
+		models.Figure{Image: models.Image{Src: "arch.png", Alt: "图一"}, Caption: []models.Inline{models.Text{Content: "图一"}}},
+		models.Figure{
+			Image:   models.Image{Src: "arch.png", Alt: "架构", Attr: models.Attributes{ID: "fig:arch"}},
+			Caption: []models.Inline{models.Text{Content: "系统"}, models.Bold{Content: []models.Inline{models.Text{Content: "架构"}}}},
+		},
+	}}
+	xml := newGenerator(Options{ResourceDir: dir, FigureLabel: "图"}).documentXML(doc)
+
+	if strings.Count(xml, `<w:pStyle w:val="Caption"/>`) != 2 {
+		t.Error("每个图都应生成Caption样式的题注段落")
+	}
+	if !strings.Contains(xml, `<w:bookmarkStart w:id="1" w:name="fig_arch"/><w:r><w:t xml:space="preserve">图 </w:t></w:r><w:fldSimple w:instr=" SEQ Figure \* ARABIC "><w:r><w:t>2</w:t></w:r></w:fldSimple><w:bookmarkEnd w:id="1"/>`) {
+		t.Errorf("第二个图的题注编号或书签错误，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:fldSimple w:instr=" REF fig_arch \h "><w:r><w:t xml:space="preserve">图 2</w:t></w:r></w:fldSimple>`) {
+		t.Error("前向引用应生成指向书签的REF域并带有正确编号")
+	}
+	if !strings.Contains(xml, `TOC \h \z \c "Figure"`) {
+		t.Error("图目录应使用带 \\c \"Figure\" 开关的TOC域")
+	}
+	if !strings.Contains(xml, `<w:hyperlink w:anchor="fig_arch" w:history="1"><w:r><w:t xml:space="preserve">图 2: 系统架构</w:t></w:r></w:hyperlink>`) {
+		t.Error("图目录应预先填入各图的题注")
+	}
+	if !strings.Contains(xml, `<w:hyperlink w:anchor="_Figure1"`) {
+		t.Error("没有标识符的图应使用自动生成的书签")
+	}
+
+	// 找不到被引用的图时输出原文并给出警告
+	var warnings []string
+	g := newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }})
+	if xml := g.referenceXML(models.Reference{ID: "fig:x"}); xml != `<w:r><w:t>@fig:x</w:t></w:r>` {
+		t.Errorf("找不到被引用的图时应输出原文，实际为'%s'", xml)
+	}
+	if len(warnings) != 1 || warnings[0] != "未找到被引用的图: @fig:x" {
+		t.Errorf("找不到被引用的图时应给出警告，实际为%q", warnings)
+	}
+}
+
+func TestBookmarkName(t *testing.T) {
+	testCases := map[string]string{
+		"fig:arch":              "fig_arch",
+		"1-intro":               "x1_intro",
+		"图:架构":                  "图_架构",
+		strings.Repeat("a", 50): strings.Repeat("a", 40),
+	}
+	for id, expected := range testCases {
+		if got := bookmarkName(id); got != expected {
+			t.Errorf("bookmarkName(%q) 期望为'%s'，实际为'%s'", id, expected, got)
+		}
+	}
+}
@@ -4,13 +4,106 @@ import (
 	"archive/zip"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"goffice/internal/models"
 	"goffice/pkg/latex"
 )
 
+// generator 在生成document.xml的同时收集关系和媒体文件等附属部件
+type generator struct {
+	opts          Options
+	rels          []relationship            // document.xml的关系，rId1固定指向styles.xml
+	media         []part                    // 嵌入的媒体文件
+	images        map[string]picture        // 已嵌入的图片，按文件路径缓存
+	drawingID     int                       // 绘图对象的编号，在文档内必须唯一
+	figures       []figureEntry             // 文档中所有的图，按出现顺序编号
+	figuresDone   int                       // 已生成的图的数量
+	headings      []headingEntry            // 文档中所有的标题
+	headingsDone  int                       // 已生成的标题的数量
+	hyperlinks    map[string]string         // 外部链接地址到关系ID的映射
+	bookmarks     int                       // 已使用的书签编号
+	footnotes     map[string][]models.Block // 脚注定义，键为脚注标签
+	notes         []noteEntry               // 已引用的脚注，按引用顺序编号
+	inNote        bool                      // 是否正在生成脚注内容
+	comments      [][]models.Inline         // 批注内容，按出现顺序编号
+	inComment     bool                      // 是否正在生成批注内容
+	commentDate   time.Time                 // 批注的日期
+	err           error                     // 生成过程中遇到的第一个错误
+	hasFields     bool                      // 是否包含打开时需要更新的域，如目录
+	headingNumID  int                       // 标题多级编号在numbering.xml中的编号
+	numberFormats []numberingLevel          // 标题各级的编号格式
+	reference     *referenceParts           // 从参考文档复用的部件，未指定参考文档时为nil
+	sectPr        string                    // 当前节的节属性，最后一节的写在正文末尾
+	section       sectionProps              // 当前节的页面设置
+	lang          string                    // 文档语言，来自选项或元数据
+	headerParts   []part                    // 生成的页眉页脚部件
+	topLevel      bool                      // 正在生成的块是否直接位于正文中，只有这样的块可以分节
+	now           time.Time                 // 生成文档的时间，用于文档属性和页眉页脚中的日期
+}
+
+// warn 报告不影响生成文档的输入问题，如无法嵌入的图片，由调用者决定如何显示
+func (g *generator) warn(format string, args ...interface{}) {
+	if g.opts.Warn != nil {
+		g.opts.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// newGenerator 创建文档生成器
+func newGenerator(opts Options) *generator {
+	g := &generator{
+		opts:       opts,
+		rels:       []relationship{{ID: "rId1", Type: relTypeStyles, Target: "styles.xml"}},
+		images:     map[string]picture{},
+		hyperlinks: map[string]string{},
+
+		headingNumID:  1,
+		numberFormats: headingNumberFormats,
+		section:       defaultSection(),
+		now:           time.Now(),
+	}
+	if p, ok := presets[opts.Preset]; ok {
+		g.opts.NumberHeadings = true
+		g.numberFormats = p.numbering
+		g.section = p.section
+	}
+	if err := g.section.apply(opts.Page); err != nil {
+		g.err = err
+	}
+	g.sectPr = g.section.XML()
+	if date, err := parseCommentDate(opts.CommentDate, g.now); err != nil {
+		g.err = err
+	} else {
+		g.commentDate = date
+	}
+	return g
+}
+
+// addRelationship 为document.xml添加关系并返回生成的关系ID
+func (g *generator) addRelationship(relType, target string, external bool) string {
+	id := fmt.Sprintf("rId%d", len(g.rels)+1)
+	g.rels = append(g.rels, relationship{ID: id, Type: relType, Target: target, External: external})
+	return id
+}
+
+// withPart 为document.xml之外的部件（如脚注）生成内容，该部件使用独立的关系ID，返回其关系
+func (g *generator) withPart(fn func()) []relationship {
+	rels, images, hyperlinks := g.rels, g.images, g.hyperlinks
+	g.rels, g.images, g.hyperlinks = nil, map[string]picture{}, map[string]string{}
+	fn()
+	partRels := g.rels
+	g.rels, g.images, g.hyperlinks = rels, images, hyperlinks
+	return partRels
+}
+
 // GenerateDocumentXML 将文档模型转换为XML
 func GenerateDocumentXML(doc models.Document) string {
+	return newGenerator(Options{}).documentXML(doc)
+}
+
+// documentXML 生成document.xml的内容
+func (g *generator) documentXML(doc models.Document) string {
 	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <w:document 
     xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
@@ -24,130 +117,309 @@ func GenerateDocumentXML(doc models.Document) string {
     xmlns:w10="urn:schemas-microsoft-com:office:word"
     xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"
     xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml"
+    xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
     mc:Ignorable="w14 w15 mv">
     <w:body>`
 
-	fmt.Println("开始生成XML文档")
-	for i, block := range doc.Blocks {
-		fmt.Printf("处理第 %d 个块，类型: %s\n", i+1, block.Type())
-		switch b := block.(type) {
-		case models.Header:
-			fmt.Printf("标题级别: %d, 内容: %s\n", b.Level, b.Text)
-			xml += fmt.Sprintf(`<w:p><w:pPr><w:pStyle w:val="Heading%d"/></w:pPr><w:r><w:t>%s</w:t></w:r></w:p>`, b.Level, b.Text)
-		case models.Paragraph:
-			fmt.Printf("段落包含 %d 个内联元素\n", len(b.Inlines))
-			xml += `<w:p><w:pPr><w:rPr></w:rPr></w:pPr>`
-			for j, inline := range b.Inlines {
-				fmt.Printf("  处理段落中第 %d 个内联元素，类型: %s\n", j+1, inline.InlineType())
-				switch i := inline.(type) {
-				case models.Text:
-					fmt.Printf("  文本内容: %s\n", i.Content)
-					xml += fmt.Sprintf(`<w:r><w:t>%s</w:t></w:r>`, i.Content)
-				case models.Bold:
-					if len(i.Content) > 0 {
-						fmt.Printf("  粗体内容: %v\n", i.Content[0])
-						if textContent, ok := i.Content[0].(models.Text); ok {
-							xml += `<w:r><w:rPr><w:b/></w:rPr><w:t>` + textContent.Content + `</w:t></w:r>`
-						}
-					}
-				case models.Math:
-					fmt.Printf("  数学公式(LaTeX): %s\n", i.LaTeX)
-					mathXml := latex.ToOMML(i.LaTeX)
-					fmt.Printf("  生成的数学XML: %s\n", mathXml)
-					xml += `<m:oMathPara><m:oMath>` + mathXml + `</m:oMath></m:oMathPara>`
-				}
+	g.lang = g.opts.Lang
+	if g.lang == "" {
+		g.lang = doc.Metadata.Lang
+	}
+	g.headersFooters(doc.Metadata)
+	// 图的书签先于标题确定，交叉引用的书签名不受标题影响
+	bookmarks := map[string]bool{}
+	g.collectFigures(doc.Blocks, bookmarks)
+	g.collectHeadings(doc.Blocks, bookmarks)
+	g.collectFootnotes(doc.Footnotes)
+	g.comments = nil
+	if g.opts.TitleBlock {
+		xml += titleBlockXML(doc.Metadata)
+	}
+	if g.opts.TOC {
+		xml += g.tocXML()
+	}
+	if count, gap := g.documentColumns(doc.Metadata); count > 1 {
+		// 标题信息和目录单独成节，不分栏
+		if strings.HasSuffix(xml, "<w:body>") {
+			g.useColumns(count, gap)
+		} else {
+			xml += g.setColumns(count, gap)
+		}
+	}
+	for _, block := range doc.Blocks {
+		g.topLevel = true
+		blockXml := g.blockXML(block)
+		// 相邻的两个表格之间需要段落分隔，否则Word会将其合并
+		if strings.HasSuffix(xml, `</w:tbl>`) && strings.HasPrefix(blockXml, `<w:tbl>`) {
+			xml += `<w:p/>`
+		}
+		xml += blockXml
+	}
+	xml += g.sectPr + `</w:body></w:document>`
+	return xml
+}
+
+// blockXML 将单个块元素转换为XML
+func (g *generator) blockXML(block models.Block) string {
+	topLevel := g.topLevel
+	g.topLevel = false
+	switch b := block.(type) {
+	case models.Header:
+		return g.headerXML(b)
+	case models.Paragraph:
+		return g.paragraphXML(b, "")
+	case models.Math:
+		// 处理块级数学公式
+		mathXml := latex.ToOMML(b.LaTeX)
+		return `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><m:oMathPara><m:oMath>` + mathXml + `</m:oMath></m:oMathPara></w:p>`
+	case models.Figure:
+		return g.figureXML(b)
+	case models.ListOfFigures:
+		return g.listOfFiguresXML()
+	case models.TableOfContents:
+		return g.tocXML()
+	case models.BlockQuote:
+		return g.blockQuoteXML(b, 1)
+	case models.Callout:
+		return g.calloutXML(b)
+	case models.RawBlock:
+		return g.rawXML(b.Format, b.Content)
+	case models.DefinitionList:
+		return g.definitionListXML(b)
+	case models.HorizontalRule:
+		return `<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="auto"/></w:pBdr></w:pPr></w:p>`
+	case models.SectionBreak:
+		if !topLevel {
+			// 表格、引用块和分栏块中不能分节
+			g.warn("忽略表格、引用块、提示块或分栏块中的分节符")
+			return ""
+		}
+		return g.sectionBreakXML(b)
+	case models.Columns:
+		if !topLevel {
+			g.warn("表格、引用块、提示块或分栏块中的 %d 栏分栏块不分栏", b.Count)
+			var xml string
+			for _, block := range b.Blocks {
+				xml += g.blockXML(block)
 			}
-			xml += `</w:p>`
+			return xml
+		}
+		return g.columnsXML(b)
+	case models.ColumnBreak:
+		return `<w:p><w:r><w:br w:type="column"/></w:r></w:p>`
+	case models.PageBreak:
+		return `<w:p><w:r><w:br w:type="page"/></w:r></w:p>`
+	case models.Table:
+		return g.tableXML(b)
+	}
+	return ""
+}
+
+// paragraphXML 将段落转换为XML，props为附加的段落属性
+func (g *generator) paragraphXML(p models.Paragraph, props string) string {
+	return `<w:p><w:pPr>` + props + `<w:rPr></w:rPr></w:pPr>` + g.inlinesXML(p.Inlines) + `</w:p>`
+}
+
+// inlinesXML 将内联元素转换为XML
+func (g *generator) inlinesXML(inlines []models.Inline) string {
+	return g.runsXML(inlines, runProps{})
+}
+
+// runsXML 将内联元素转换为XML，rPr为外层的格式，如链接的字符样式，嵌套的格式在其基础上设置
+func (g *generator) runsXML(inlines []models.Inline, rPr runProps) string {
+	xml := ""
+	for _, inline := range inlines {
+		switch i := inline.(type) {
+		case models.Text:
+			xml += textRunXML(rPr, i.Content)
+		case models.Bold:
+			bold := rPr
+			bold.bold = true
+			xml += g.runsXML(i.Content, bold)
 		case models.Math:
-			// 处理块级数学公式
-			fmt.Printf("块级数学公式(LaTeX): %s\n", b.LaTeX)
-			mathXml := latex.ToOMML(b.LaTeX)
-			fmt.Printf("生成的块级数学XML: %s\n", mathXml)
-			xml += `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><m:oMathPara><m:oMath>` + mathXml + `</m:oMath></m:oMathPara></w:p>`
+			mathXml := latex.ToOMML(i.LaTeX)
+			xml += `<m:oMathPara><m:oMath>` + mathXml + `</m:oMath></m:oMathPara>`
+		case models.Image:
+			xml += g.imageXML(i)
+		case models.Reference:
+			xml += g.referenceXML(i)
+		case models.Link:
+			xml += g.linkXML(i, rPr)
+		case models.Code:
+			code := rPr
+			if code.style == "" {
+				code.style = "VerbatimChar"
+			} else {
+				// 一个文本段只能有一个字符样式，链接中的代码保留链接样式并使用等宽字体
+				code.fonts.ascii, code.fonts.hAnsi, code.fonts.cs = "Consolas", "Consolas", "Consolas"
+			}
+			xml += `<w:r><w:rPr>` + code.XML() + `</w:rPr><w:t xml:space="preserve">` + escapeXML(i.Content) + `</w:t></w:r>`
+		case models.Subscript:
+			sub := rPr
+			sub.vertAlign = "subscript"
+			xml += g.runsXML(i.Content, sub)
+		case models.Superscript:
+			sup := rPr
+			sup.vertAlign = "superscript"
+			xml += g.runsXML(i.Content, sup)
+		case models.RawInline:
+			xml += g.rawXML(i.Format, i.Content)
+		case models.LineBreak:
+			xml += `<w:r><w:br/></w:r>`
+		case models.FootnoteReference:
+			xml += g.noteReferenceXML(i)
+		case models.Comment:
+			xml += g.commentXML(i, rPr)
 		}
 	}
-	xml += `</w:body></w:document>`
 	return xml
 }
 
+// walkBlocks 按文档顺序遍历块元素，包括表格单元格、引用块和定义列表中的块
+func walkBlocks(blocks []models.Block, fn func(models.Block)) {
+	for _, block := range blocks {
+		fn(block)
+		switch b := block.(type) {
+		case models.Table:
+			for _, row := range append(append([]models.TableRow{}, b.Head...), b.Rows...) {
+				for _, cell := range row.Cells {
+					walkBlocks(cell.Blocks, fn)
+				}
+			}
+		case models.BlockQuote:
+			walkBlocks(b.Blocks, fn)
+		case models.Callout:
+			walkBlocks(b.Blocks, fn)
+		case models.Columns:
+			walkBlocks(b.Blocks, fn)
+		case models.DefinitionList:
+			for _, item := range b.Items {
+				for _, definition := range item.Definitions {
+					walkBlocks(definition, fn)
+				}
+			}
+		}
+	}
+}
+
 // CreateDOCX 创建DOCX文件
 func CreateDOCX(doc models.Document, filename string) error {
-	fmt.Println("开始创建DOCX文件:", filename)
+	return CreateDOCXWithOptions(doc, filename, Options{})
+}
+
+// CreateDOCXWithOptions 按指定选项创建DOCX文件
+func CreateDOCXWithOptions(doc models.Document, filename string, opts Options) error {
+	if _, ok := presets[opts.Preset]; opts.Preset != "" && !ok {
+		return fmt.Errorf("未知的版式预设: %s", opts.Preset)
+	}
+	g := newGenerator(opts)
+	if g.err != nil {
+		return g.err
+	}
+	if opts.ReferenceDoc != "" {
+		ref, err := loadReference(opts.ReferenceDoc)
+		if err != nil {
+			return err
+		}
+		g.useReference(ref)
+	}
+	documentXml := g.documentXML(doc)
+
+	parts := []part{
+		{Name: "word/document.xml", ContentType: contentTypeDocument, Data: documentXml},
+		{Name: "word/styles.xml", ContentType: contentTypeStyles, Data: g.stylesXML()},
+	}
+	if len(g.notes) > 0 {
+		kind := g.opts.noteKind()
+		var notesXml string
+		rels := g.withPart(func() { notesXml = g.notesXML() })
+		parts = append(parts, part{Name: "word/" + kind.part + ".xml", ContentType: kind.contentType, Data: notesXml})
+		if len(rels) > 0 {
+			parts = append(parts, part{Name: "word/_rels/" + kind.part + ".xml.rels", Data: relationshipsXML(rels)})
+		}
+		g.addRelationship(kind.relType, kind.part+".xml", false)
+	}
+	// 脚注中也可能有批注，所以在脚注之后生成
+	if len(g.comments) > 0 {
+		var commentsXml string
+		rels := g.withPart(func() { commentsXml = g.commentsXML() })
+		parts = append(parts, part{Name: "word/comments.xml", ContentType: contentTypeComments, Data: commentsXml})
+		if len(rels) > 0 {
+			parts = append(parts, part{Name: "word/_rels/comments.xml.rels", Data: relationshipsXML(rels)})
+		}
+		g.addRelationship(relTypeComments, "comments.xml", false)
+	}
+	numbering, settings := "", g.settingsXML()
+	if g.reference != nil {
+		numbering = g.reference.numbering
+		if g.reference.settings != "" {
+			settings = g.mergeSettings(g.reference.settings)
+		}
+	}
+	if g.hasNumberedHeadings() {
+		if numbering != "" {
+			numbering = g.mergeNumbering(numbering)
+		} else {
+			numbering = g.numberingXML()
+		}
+	}
+	if numbering != "" {
+		parts = append(parts, part{Name: "word/numbering.xml", ContentType: contentTypeNumbering, Data: numbering})
+		g.addRelationship(relTypeNumbering, "numbering.xml", false)
+	}
+	parts = append(parts, part{Name: "word/settings.xml", ContentType: contentTypeSettings, Data: settings})
+	g.addRelationship(relTypeSettings, "settings.xml", false)
+	if g.reference != nil {
+		parts = append(parts, g.reference.parts...)
+	}
+	parts = append(parts, g.headerParts...)
+	parts = append(parts, g.media...)
+	meta := doc.Metadata
+	meta.Lang = g.lang
+	parts = append(parts,
+		part{Name: "docProps/core.xml", ContentType: contentTypeCore, Data: coreXML(meta, g.now)},
+		part{Name: "docProps/app.xml", ContentType: contentTypeApp, Data: appXML(g.documentStats(doc), meta)},
+	)
+	packageRels := []relationship{
+		{ID: "rId1", Type: relTypeOfficeDocument, Target: "word/document.xml"},
+		{ID: "rId2", Type: relTypeCoreProperties, Target: "docProps/core.xml"},
+		{ID: "rId3", Type: relTypeAppProperties, Target: "docProps/app.xml"},
+	}
+	if custom := customXML(meta); custom != "" {
+		parts = append(parts, part{Name: "docProps/custom.xml", ContentType: contentTypeCustom, Data: custom})
+		packageRels = append(packageRels, relationship{ID: "rId4", Type: relTypeCustomProperties, Target: "docProps/custom.xml"})
+	}
+
+	// 生成的内容有误时不创建文件，避免留下损坏的DOCX
+	if g.err != nil {
+		return g.err
+	}
+
 	f, err := os.Create(filename)
 	if err != nil {
-		fmt.Println("创建文件失败:", err)
 		return err
 	}
 	defer f.Close()
 	w := zip.NewWriter(f)
 	defer w.Close()
 
-	fmt.Println("添加[Content_Types].xml")
-	addFileToZip(w, "[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
-    <Default Extension="xml" ContentType="application/xml"/>
-    <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
-    <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
-    <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
-</Types>`)
-
-	fmt.Println("添加_rels/.rels")
-	addFileToZip(w, "_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
-    <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
-</Relationships>`)
-
-	fmt.Println("添加word/_rels/document.xml.rels")
-	addFileToZip(w, "word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
-    <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
-</Relationships>`)
-
-	fmt.Println("添加word/styles.xml")
-	addFileToZip(w, "word/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
-    <w:style w:type="paragraph" w:styleId="Heading1">
-        <w:name w:val="Heading 1"/>
-        <w:pPr>
-            <w:spacing w:before="240" w:after="120"/>
-            <w:outlineLvl w:val="0"/>
-        </w:pPr>
-        <w:rPr>
-            <w:b/>
-            <w:sz w:val="36"/>
-        </w:rPr>
-    </w:style>
-    <w:style w:type="paragraph" w:styleId="Heading2">
-        <w:name w:val="Heading 2"/>
-        <w:pPr>
-            <w:spacing w:before="240" w:after="120"/>
-            <w:outlineLvl w:val="1"/>
-        </w:pPr>
-        <w:rPr>
-            <w:b/>
-            <w:sz w:val="32"/>
-        </w:rPr>
-    </w:style>
-    <w:style w:type="paragraph" w:styleId="Heading3">
-        <w:name w:val="Heading 3"/>
-        <w:pPr>
-            <w:spacing w:before="240" w:after="120"/>
-            <w:outlineLvl w:val="2"/>
-        </w:pPr>
-        <w:rPr>
-            <w:b/>
-            <w:sz w:val="28"/>
-        </w:rPr>
-    </w:style>
-</w:styles>`)
-
-	fmt.Println("生成document.xml")
-	documentXml := GenerateDocumentXML(doc)
-
-	fmt.Println("添加word/document.xml")
-	addFileToZip(w, "word/document.xml", documentXml)
-
-	fmt.Println("DOCX文件创建完成")
+	if err := addFileToZip(w, "[Content_Types].xml", contentTypesXML(parts)); err != nil {
+		return err
+	}
+
+	if err := addFileToZip(w, "_rels/.rels", relationshipsXML(packageRels)); err != nil {
+		return err
+	}
+
+	if err := addFileToZip(w, "word/_rels/document.xml.rels", relationshipsXML(g.rels)); err != nil {
+		return err
+	}
+
+	for _, p := range parts {
+		if err := addFileToZip(w, p.Name, p.Data); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
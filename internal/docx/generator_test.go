@@ -12,7 +12,7 @@ func TestGenerateDocumentXML(t *testing.T) {
 	// 创建一个简单文档用于测试
 	doc := models.Document{
 		Blocks: []models.Block{
-			models.Header{Level: 1, Text: "测试文档"},
+			models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "测试文档"}}},
 			models.Paragraph{
 				Inlines: []models.Inline{
 					models.Text{Content: "这是一个简单的段落。"},
@@ -74,6 +74,64 @@ func TestGenerateDocumentXML(t *testing.T) {
 	}
 }
 
+func TestBreaksXML(t *testing.T) {
+	doc := models.Document{
+		Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{models.Text{Content: "上"}, models.LineBreak{}, models.Text{Content: "下"}}},
+			models.HorizontalRule{},
+			models.PageBreak{},
+		},
+	}
+	xml := GenerateDocumentXML(doc)
+	for _, want := range []string{
+		`<w:r><w:t>上</w:t></w:r><w:r><w:br/></w:r><w:r><w:t>下</w:t></w:r>`,
+		`<w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="auto"/></w:pBdr>`,
+		`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("生成的XML缺少%s", want)
+		}
+	}
+}
+
+func TestHeaderInlinesXML(t *testing.T) {
+	doc := models.Document{
+		Blocks: []models.Block{
+			models.Header{Level: 2, Inlines: []models.Inline{
+				models.Bold{Content: []models.Inline{models.Text{Content: "安装"}}},
+				models.Code{Content: "go <install>"},
+			}},
+		},
+	}
+	xml := GenerateDocumentXML(doc)
+	want := `<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:bookmarkStart w:id="0" w:name="安装go_install"/>` +
+		`<w:r><w:rPr><w:b/><w:bCs/></w:rPr><w:t>安装</w:t></w:r>` +
+		`<w:r><w:rPr><w:rStyle w:val="VerbatimChar"/></w:rPr><w:t xml:space="preserve">go &lt;install&gt;</w:t></w:r>` +
+		`<w:bookmarkEnd w:id="0"/></w:p>`
+	if !strings.Contains(xml, want) {
+		t.Errorf("标题中的内联元素生成错误，实际XML为:\n%s", xml)
+	}
+}
+
+func TestScriptXML(t *testing.T) {
+	xml := GenerateDocumentXML(models.Document{Blocks: []models.Block{
+		models.Paragraph{Inlines: []models.Inline{
+			models.Text{Content: "H"},
+			models.Subscript{Content: []models.Inline{models.Text{Content: "2"}}},
+			models.Superscript{Content: []models.Inline{models.Text{Content: "n"}}},
+			models.Subscript{Content: []models.Inline{models.Bold{Content: []models.Inline{models.Comment{Content: []models.Inline{models.Text{Content: "x"}}}}}}},
+		}},
+	}})
+	if !strings.Contains(xml, `<w:r><w:rPr><w:vertAlign w:val="subscript"/></w:rPr><w:t>2</w:t></w:r>`) ||
+		!strings.Contains(xml, `<w:r><w:rPr><w:vertAlign w:val="superscript"/></w:rPr><w:t>n</w:t></w:r>`) {
+		t.Errorf("上下标生成错误，实际XML为:\n%s", xml)
+	}
+	// 嵌套的格式按CT_RPr的顺序写入，与嵌套的先后无关
+	if !strings.Contains(xml, `<w:r><w:rPr><w:b/><w:bCs/><w:highlight w:val="yellow"/><w:vertAlign w:val="subscript"/></w:rPr><w:t>x</w:t></w:r>`) {
+		t.Errorf("下标中的粗体格式顺序错误，实际XML为:\n%s", xml)
+	}
+}
+
 func TestCreateDOCX(t *testing.T) {
 	// 跳过创建实际DOCX文件的测试，避免文件I/O
 	t.Skip("跳过DOCX文件创建测试")
@@ -82,7 +140,7 @@ func TestCreateDOCX(t *testing.T) {
 	// 创建一个简单文档
 	doc := models.Document{
 		Blocks: []models.Block{
-			models.Header{Level: 1, Text: "测试文档"},
+			models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "测试文档"}}},
 			models.Paragraph{
 				Inlines: []models.Inline{
 					models.Text{Content: "测试段落"},
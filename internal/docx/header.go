@@ -0,0 +1,157 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// 页眉页脚部件的内容类型
+const (
+	contentTypeHeader = "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	contentTypeFooter = "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+)
+
+// headerFooterKind 描述页眉或页脚部件
+type headerFooterKind struct {
+	name        string // 部件名的前缀，也是节属性中引用元素名的前缀
+	root        string // 部件的根元素
+	style       string // 段落样式
+	relType     string
+	contentType string
+}
+
+var (
+	headerKind = headerFooterKind{"header", "hdr", "Header", relTypeHeader, contentTypeHeader}
+	footerKind = headerFooterKind{"footer", "ftr", "Footer", relTypeFooter, contentTypeFooter}
+)
+
+// placeholderPattern 匹配页眉页脚模板中的占位符
+var placeholderPattern = regexp.MustCompile(`\{(title|author|date|page|pages)\}`)
+
+// headersFooters 按选项生成页眉页脚部件，并在节属性中引用它们。
+// 选项中设置了页眉或页脚时替换参考文档中对应的页眉或页脚
+func (g *generator) headersFooters(meta models.Metadata) {
+	hf := g.opts.HeaderFooter
+	first, even := hf.firstPage(), hf.oddEven()
+	for _, kind := range []headerFooterKind{headerKind, footerKind} {
+		templates := map[string]string{"default": hf.Header, "first": hf.FirstHeader, "even": hf.EvenHeader}
+		if kind == footerKind {
+			templates = map[string]string{"default": hf.Footer, "first": hf.FirstFooter, "even": hf.EvenFooter}
+		}
+		if templates["default"] == "" && templates["first"] == "" && templates["even"] == "" {
+			continue
+		}
+		g.sectPr = removeSectPrElements(g.sectPr, "w:"+kind.name+"Reference")
+		var references string
+		for _, typ := range []string{"default", "first", "even"} {
+			if typ == "first" && !first || typ == "even" && !even {
+				continue
+			}
+			// 首页或偶数页的模板为空时生成空白的页眉页脚，不沿用奇数页的内容
+			name := g.partName(kind.name)
+			g.headerParts = append(g.headerParts, part{Name: "word/" + name, ContentType: kind.contentType, Data: g.headerFooterXML(kind, templates[typ], meta)})
+			id := g.addRelationship(kind.relType, name, false)
+			references += fmt.Sprintf(`<w:%sReference w:type="%s" r:id="%s"/>`, kind.name, typ, id)
+		}
+		g.sectPr = insertSectPrElement(g.sectPr, references)
+	}
+	if first {
+		g.sectPr = setSectPrElement(g.sectPr, `<w:titlePg/>`)
+	}
+}
+
+// partName 返回未被使用的部件名，如 header1.xml，避免与参考文档中复制的部件重名
+func (g *generator) partName(prefix string) string {
+	used := map[string]bool{}
+	for _, p := range g.headerParts {
+		used[p.Name] = true
+	}
+	if g.reference != nil {
+		for _, p := range g.reference.parts {
+			used[p.Name] = true
+		}
+	}
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("%s%d.xml", prefix, n)
+		if !used["word/"+name] {
+			return name
+		}
+	}
+}
+
+// headerFooterXML 生成页眉或页脚部件的内容
+func (g *generator) headerFooterXML(kind headerFooterKind, template string, meta models.Metadata) string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:` + kind.root + `
+    xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+    xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`
+	pPr := `<w:pStyle w:val="` + kind.style + `"/>`
+	columns := strings.Split(template, "|")
+	if len(columns) == 1 {
+		pPr += `<w:jc w:val="center"/>`
+	}
+	xml += `<w:p><w:pPr>` + pPr + `</w:pPr>`
+	for i, column := range columns {
+		if i > 0 {
+			xml += `<w:r><w:tab/></w:r>`
+			// 只有两部分时第二部分跳过居中的制表位，右对齐
+			if len(columns) == 2 {
+				xml += `<w:r><w:tab/></w:r>`
+			}
+		}
+		xml += g.templateXML(column, meta)
+	}
+	return xml + `</w:p></w:` + kind.root + `>`
+}
+
+// templateXML 将模板中的文本转换为文字块，占位符替换为元数据或域
+func (g *generator) templateXML(template string, meta models.Metadata) string {
+	var xml string
+	text := func(s string) {
+		if s != "" {
+			xml += textRunXML(runProps{}, s)
+		}
+	}
+	field := func(instr, result string) {
+		xml += `<w:fldSimple w:instr="` + escapeXML(instr) + `"><w:r><w:t>` + escapeXML(result) + `</w:t></w:r></w:fldSimple>`
+	}
+	last := 0
+	for _, m := range placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		text(template[last:m[0]])
+		last = m[1]
+		switch template[m[2]:m[3]] {
+		case "title":
+			text(meta.Title)
+		case "author":
+			text(joinAuthors(meta.Authors))
+		case "date":
+			// 没有日期元数据时插入当天的日期
+			if meta.Date != "" {
+				text(meta.Date)
+			} else {
+				field(` DATE \@ "yyyy-MM-dd" `, g.now.Format("2006-01-02"))
+			}
+		case "page":
+			field(" PAGE ", "1")
+		case "pages":
+			field(" NUMPAGES ", "1")
+		}
+	}
+	text(template[last:])
+	return xml
+}
+
+// joinAuthors 连接多位作者的姓名，中文姓名以顿号分隔
+func joinAuthors(authors []string) string {
+	for _, author := range authors {
+		for _, r := range author {
+			if isEastAsian(r) {
+				return strings.Join(authors, "、")
+			}
+		}
+	}
+	return strings.Join(authors, ", ")
+}
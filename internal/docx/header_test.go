@@ -0,0 +1,129 @@
+package docx
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestHeaderFooter(t *testing.T) {
+	meta := models.Metadata{Title: "年度报告", Authors: []string{"张三", "李四"}}
+
+	t.Run("占位符", func(t *testing.T) {
+		g := newGenerator(Options{})
+		got := g.templateXML("{title} — {author} 第{page}页/共{pages}页 {unknown} & {date}", meta)
+		for _, want := range []string{
+			`<w:t>年度报告</w:t>`,
+			`<w:t>张三、李四</w:t>`,
+			`<w:fldSimple w:instr=" PAGE "><w:r><w:t>1</w:t></w:r></w:fldSimple>`,
+			`<w:fldSimple w:instr=" NUMPAGES "><w:r><w:t>1</w:t></w:r></w:fldSimple>`,
+			`{unknown} &amp; `,
+			`<w:fldSimple w:instr=" DATE \@ &#34;yyyy-MM-dd&#34; ">`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("缺少 %s:\n%s", want, got)
+			}
+		}
+		got = g.templateXML("{author}，{date}", models.Metadata{Authors: []string{"Ada", "Alan"}, Date: "2024年5月"})
+		if got != `<w:r><w:t>Ada, Alan</w:t></w:r><w:r><w:t>，</w:t></w:r><w:r><w:t>2024年5月</w:t></w:r>` {
+			t.Errorf("作者和日期错误: %s", got)
+		}
+	})
+
+	t.Run("左中右对齐", func(t *testing.T) {
+		g := newGenerator(Options{})
+		if got := g.headerFooterXML(footerKind, "{page}", meta); !strings.Contains(got, `<w:p><w:pPr><w:pStyle w:val="Footer"/><w:jc w:val="center"/></w:pPr><w:fldSimple`) ||
+			!strings.Contains(got, `<w:ftr`) {
+			t.Errorf("单独的页码应居中:\n%s", got)
+		}
+		got := g.headerFooterXML(headerKind, "{title}|第{page}页", meta)
+		if !strings.Contains(got, `<w:t>年度报告</w:t></w:r><w:r><w:tab/></w:r><w:r><w:tab/></w:r><w:r><w:t>第</w:t></w:r>`) {
+			t.Errorf("两部分应分别左对齐和右对齐:\n%s", got)
+		}
+		got = g.headerFooterXML(headerKind, "左|中|右", meta)
+		if strings.Count(got, `<w:tab/>`) != 2 || strings.Contains(got, `<w:jc`) {
+			t.Errorf("三部分应以两个制表符分隔:\n%s", got)
+		}
+	})
+
+	t.Run("首页和奇偶页", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.docx")
+		opts := Options{HeaderFooter: HeaderFooter{
+			Header: "{title}", EvenHeader: "{author}", Footer: "第{page}页，共{pages}页", DifferentFirst: true,
+		}}
+		doc := models.Document{Metadata: meta, Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "正文"}}}}}
+		if err := CreateDOCXWithOptions(doc, out, opts); err != nil {
+			t.Fatal(err)
+		}
+		files := readDOCX(t, out)
+		m := regexp.MustCompile(`<w:sectPr>((?:<w:(?:header|footer)Reference [^>]*/>)*)<w:pgSz[^>]*/><w:pgMar[^>]*/><w:titlePg/></w:sectPr>`).FindStringSubmatch(files["word/document.xml"])
+		if m == nil {
+			t.Fatalf("节属性中缺少页眉页脚引用:\n%s", files["word/document.xml"])
+		}
+		rels := files["word/_rels/document.xml.rels"]
+		refs := regexp.MustCompile(`<w:(header|footer)Reference w:type="(\w+)" r:id="(rId\d+)"/>`).FindAllStringSubmatch(m[1], -1)
+		var types []string
+		for _, ref := range refs {
+			types = append(types, ref[1]+":"+ref[2])
+			target := regexp.MustCompile(`Id="` + ref[3] + `" Type="[^"]*/` + ref[1] + `" Target="(` + ref[1] + `\d\.xml)"`).FindStringSubmatch(rels)
+			if target == nil {
+				t.Errorf("%s 没有对应的关系", ref[3])
+				continue
+			}
+			if files["word/"+target[1]] == "" || !strings.Contains(files["[Content_Types].xml"], `PartName="/word/`+target[1]+`"`) {
+				t.Errorf("缺少部件 %s", target[1])
+			}
+		}
+		if got := strings.Join(types, ","); got != "header:default,header:first,header:even,footer:default,footer:first,footer:even" {
+			t.Errorf("页眉页脚引用错误: %s", got)
+		}
+		if !strings.Contains(files["word/header1.xml"], "年度报告") || strings.Contains(files["word/header2.xml"], "<w:r>") ||
+			!strings.Contains(files["word/header3.xml"], "张三、李四") {
+			t.Error("页眉内容错误")
+		}
+		if !strings.Contains(files["word/settings.xml"], `<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:evenAndOddHeaders/>`) {
+			t.Errorf("奇偶页不同时应设置 evenAndOddHeaders:\n%s", files["word/settings.xml"])
+		}
+		if !strings.Contains(files["word/styles.xml"], `w:styleId="Footer"`) {
+			t.Error("缺少页脚样式")
+		}
+	})
+
+	t.Run("替换参考文档的页眉", func(t *testing.T) {
+		dir := t.TempDir()
+		reference := filepath.Join(dir, "reference.docx")
+		writeZip(t, reference, map[string]string{
+			"[Content_Types].xml": `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+				`<Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/header1.xml" ContentType="` + contentTypeHeader + `"/></Types>`,
+			"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+				`<w:sectPr><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:body></w:document>`,
+			"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId1" Type="` + relTypeHeader + `" Target="header1.xml"/></Relationships>`,
+			"word/header1.xml": `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>公司页眉</w:t></w:r></w:p></w:hdr>`,
+		})
+
+		g := newGenerator(Options{ReferenceDoc: reference, HeaderFooter: HeaderFooter{Footer: "{page}"}})
+		ref, err := loadReference(reference)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.useReference(ref)
+		g.documentXML(models.Document{})
+		if !strings.Contains(g.sectPr, `<w:headerReference w:type="default" r:id="rId2"/><w:footerReference w:type="default" r:id="rId3"/>`) {
+			t.Errorf("只设置页脚时应保留参考文档的页眉:\n%s", g.sectPr)
+		}
+
+		g = newGenerator(Options{ReferenceDoc: reference, HeaderFooter: HeaderFooter{Header: "{title}"}})
+		g.useReference(ref)
+		g.documentXML(models.Document{Metadata: meta})
+		if len(g.headerParts) != 1 || g.headerParts[0].Name != "word/header2.xml" {
+			t.Fatalf("生成的页眉不应与参考文档的页眉重名: %+v", g.headerParts)
+		}
+		if strings.Count(g.sectPr, "<w:headerReference") != 1 || !strings.Contains(g.sectPr, `r:id="rId3"`) {
+			t.Errorf("应替换参考文档的页眉:\n%s", g.sectPr)
+		}
+	})
+}
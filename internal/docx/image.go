@@ -0,0 +1,145 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// 长度单位换算为EMU（English Metric Unit）
+const (
+	emuPerPixel = 9525 // 按96 DPI计算
+	emuPerTwip  = 635
+	emuPerInch  = 914400
+	emuPerCm    = 360000
+	emuPerMm    = 36000
+	emuPerPoint = 12700
+)
+
+// picture 记录已嵌入的图片
+type picture struct {
+	relID  string
+	width  int // 像素宽度
+	height int // 像素高度
+}
+
+// imageXML 嵌入图片并生成内联图片的XML，图片无法读取时退回为替代文本，没有替代文本时为图片路径
+func (g *generator) imageXML(img models.Image) string {
+	pic, err := g.embedImage(img.Src)
+	if err != nil {
+		g.warn("无法嵌入图片 %s，以文本代替: %v", img.Src, err)
+		text := img.Alt
+		if strings.TrimSpace(text) == "" {
+			text = img.Src
+		}
+		return textRunXML(runProps{}, text)
+	}
+	cx, cy := imageExtent(img.Attr, pic.width, pic.height, int64(g.textWidth())*emuPerTwip)
+	g.drawingID++
+	name := filepath.Base(img.Src)
+	descr := escapeXML(img.Alt)
+	title := ""
+	if img.Title != "" {
+		title = ` title="` + escapeXML(img.Title) + `"`
+	}
+	return fmt.Sprintf(`<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">`+
+		`<wp:extent cx="%d" cy="%d"/><wp:effectExtent l="0" t="0" r="0" b="0"/>`+
+		`<wp:docPr id="%d" name="Picture %d" descr="%s"%s/>`+
+		`<wp:cNvGraphicFramePr><a:graphicFrameLocks xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" noChangeAspect="1"/></wp:cNvGraphicFramePr>`+
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">`+
+		`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:nvPicPr><pic:cNvPr id="0" name="%s" descr="%s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`+
+		`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`,
+		cx, cy, g.drawingID, g.drawingID, descr, title, escapeXML(name), descr, pic.relID, cx, cy)
+}
+
+// embedImage 读取图片文件并添加到word/media目录，同一文件只嵌入一次
+func (g *generator) embedImage(src string) (picture, error) {
+	if strings.Contains(src, "://") {
+		return picture{}, fmt.Errorf("不支持远程图片")
+	}
+	path := src
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.opts.ResourceDir, path)
+	}
+	if pic, ok := g.images[path]; ok {
+		return pic, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return picture{}, err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return picture{}, fmt.Errorf("无法识别图片格式: %v", err)
+	}
+	name := fmt.Sprintf("media/image%d.%s", len(g.media)+1, format)
+	g.media = append(g.media, part{Name: "word/" + name, Data: string(data)})
+	pic := picture{
+		relID:  g.addRelationship(relTypeImage, name, false),
+		width:  config.Width,
+		height: config.Height,
+	}
+	g.images[path] = pic
+	return pic, nil
+}
+
+// imageExtent 根据图片属性计算显示尺寸（EMU），只指定一边时按比例缩放，超出版心宽度时等比缩小
+func imageExtent(attr models.Attributes, width, height int, maxWidth int64) (int64, int64) {
+	cx, cy := int64(width)*emuPerPixel, int64(height)*emuPerPixel
+	w, hasW := parseLength(attr.Get("width"), maxWidth)
+	h, hasH := parseLength(attr.Get("height"), 0)
+	switch {
+	case hasW && hasH:
+		cx, cy = w, h
+	case hasW && cx > 0:
+		cx, cy = w, cy*w/cx
+	case hasH && cy > 0:
+		cx, cy = cx*h/cy, h
+	}
+	if cx > maxWidth {
+		cx, cy = maxWidth, cy*maxWidth/cx
+	}
+	return cx, cy
+}
+
+// parseLength 解析带单位的长度并转换为EMU，支持 cm、mm、in、pt、px 和相对于relative的百分比
+func parseLength(s string, relative int64) (int64, bool) {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		emu    float64
+	}{
+		{"%", float64(relative) / 100},
+		{"cm", emuPerCm},
+		{"mm", emuPerMm},
+		{"in", emuPerInch},
+		{"pt", emuPerPoint},
+		{"px", emuPerPixel},
+		{"", emuPerPixel},
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), 64)
+		if err != nil || value <= 0 || u.emu == 0 {
+			return 0, false
+		}
+		return int64(math.Round(value * u.emu)), true
+	}
+	return 0, false
+}
@@ -0,0 +1,138 @@
+package docx
+
+import (
+	"archive/zip"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+// writeTestPNG 在目录中写入指定尺寸的PNG图片
+func writeTestPNG(t *testing.T, dir, name string, width, height int) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("创建测试图片失败: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("写入测试图片失败: %v", err)
+	}
+}
+
+func TestImageXML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPNG(t, dir, "diagram.png", 200, 100)
+
+	t.Run("按像素尺寸嵌入图片", func(t *testing.T) {
+		g := newGenerator(Options{ResourceDir: dir})
+		xml := g.imageXML(models.Image{Src: "diagram.png", Alt: "示意图 <1>"})
+		if !strings.Contains(xml, `<wp:extent cx="1905000" cy="952500"/>`) {
+			t.Errorf("图片尺寸错误，实际XML为:\n%s", xml)
+		}
+		if !strings.Contains(xml, `r:embed="rId2"`) {
+			t.Errorf("图片应引用关系rId2，实际XML为:\n%s", xml)
+		}
+		if !strings.Contains(xml, `descr="示意图 &lt;1&gt;"`) {
+			t.Error("替代文本应写入descr并转义")
+		}
+		if len(g.media) != 1 || g.media[0].Name != "word/media/image1.png" {
+			t.Errorf("图片应添加到word/media目录，实际为%+v", g.media)
+		}
+
+		// 同一图片只嵌入一次
+		g.imageXML(models.Image{Src: "diagram.png"})
+		if len(g.media) != 1 || len(g.rels) != 2 {
+			t.Errorf("重复引用的图片不应重复嵌入，媒体数%d，关系数%d", len(g.media), len(g.rels))
+		}
+	})
+
+	t.Run("按属性缩放图片", func(t *testing.T) {
+		g := newGenerator(Options{ResourceDir: dir})
+		xml := g.imageXML(models.Image{Src: "diagram.png", Attr: models.Attributes{Values: map[string]string{"width": "8cm"}}})
+		if !strings.Contains(xml, `<wp:extent cx="2880000" cy="1440000"/>`) {
+			t.Errorf("指定宽度后应按比例缩放，实际XML为:\n%s", xml)
+		}
+	})
+
+	t.Run("图片不存在时使用替代文本", func(t *testing.T) {
+		var warnings []string
+		g := newGenerator(Options{ResourceDir: dir, Warn: func(message string) { warnings = append(warnings, message) }})
+		xml := g.imageXML(models.Image{Src: "missing.png", Alt: "缺失"})
+		if xml != `<w:r><w:t>缺失</w:t></w:r>` || len(g.media) != 0 {
+			t.Errorf("图片不存在时应输出替代文本，实际为'%s'", xml)
+		}
+		if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "无法嵌入图片 missing.png，以文本代替") {
+			t.Errorf("图片不存在时应给出警告，实际为%q", warnings)
+		}
+		if xml := g.imageXML(models.Image{Src: "images/missing.png"}); xml != `<w:r><w:t>images/missing.png</w:t></w:r>` {
+			t.Errorf("没有替代文本时应输出图片路径，实际为'%s'", xml)
+		}
+		if xml := g.imageXML(models.Image{Src: "missing.png", Alt: " 前后空格 "}); xml != `<w:r><w:t xml:space="preserve"> 前后空格 </w:t></w:r>` {
+			t.Errorf("替代文本的空白应保留，实际为'%s'", xml)
+		}
+	})
+}
+
+func TestImageExtent(t *testing.T) {
+	max := int64(defaultTextWidth * emuPerTwip)
+	testCases := []struct {
+		name   string
+		values map[string]string
+		cx, cy int64
+	}{
+		{"原始尺寸", nil, 200 * emuPerPixel, 100 * emuPerPixel},
+		{"百分比宽度", map[string]string{"width": "50%"}, max / 2, max / 4},
+		{"指定高度", map[string]string{"height": "1in"}, 2 * emuPerInch, emuPerInch},
+		{"同时指定宽高", map[string]string{"width": "10mm", "height": "20pt"}, 10 * emuPerMm, 20 * emuPerPoint},
+		{"超出版心宽度", map[string]string{"width": "100cm"}, max, max / 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cx, cy := imageExtent(models.Attributes{Values: tc.values}, 200, 100, max)
+			if cx != tc.cx || cy != tc.cy {
+				t.Errorf("期望尺寸为%dx%d，实际为%dx%d", tc.cx, tc.cy, cx, cy)
+			}
+		})
+	}
+}
+
+func TestCreateDOCXWithImage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPNG(t, dir, "a.png", 10, 10)
+	doc := models.Document{Blocks: []models.Block{
+		models.Paragraph{Inlines: []models.Inline{models.Image{Src: "a.png", Alt: "a"}}},
+	}}
+	out := filepath.Join(dir, "out.docx")
+	if err := CreateDOCXWithOptions(doc, out, Options{ResourceDir: dir}); err != nil {
+		t.Fatalf("创建DOCX文件失败: %v", err)
+	}
+
+	r, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatalf("打开DOCX文件失败: %v", err)
+	}
+	defer r.Close()
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	if _, ok := files["word/media/image1.png"]; !ok {
+		t.Error("DOCX中缺少嵌入的图片")
+	}
+	if !strings.Contains(files["[Content_Types].xml"], `<Default Extension="png" ContentType="image/png"/>`) {
+		t.Error("[Content_Types].xml缺少png类型")
+	}
+	if !strings.Contains(files["word/_rels/document.xml.rels"], `Target="media/image1.png"`) {
+		t.Error("document.xml.rels缺少图片关系")
+	}
+}
@@ -0,0 +1,112 @@
+package docx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"goffice/internal/models"
+)
+
+// headingEntry 记录标题的书签，用于文档内链接
+type headingEntry struct {
+	level    int    // 标题级别
+	text     string // 标题的纯文本
+	slug     string // 与GitHub一致的锚点名，或 {#id} 指定的标识符
+	bookmark string // 标题处的书签名
+	number   string // 标题编号，如 1.2.3，不编号时为空
+}
+
+// collectHeadings 预先为所有标题生成锚点、书签和编号，使前向链接和目录也能定位到标题。used为已被图占用的书签名
+func (g *generator) collectHeadings(blocks []models.Block, used map[string]bool) {
+	g.headings, g.headingsDone = nil, 0
+	slugs := map[string]int{}
+	counters := make([]int, len(g.numberFormats))
+	walkBlocks(blocks, func(block models.Block) {
+		h, ok := block.(models.Header)
+		if !ok {
+			return
+		}
+		text := models.PlainText(h.Inlines)
+		slug := h.Attr.ID
+		if slug == "" {
+			slug = slugify(text)
+			// 重复的锚点依次追加 -1、-2
+			if n, ok := slugs[slug]; ok {
+				slugs[slug] = n + 1
+				slug = fmt.Sprintf("%s-%d", slug, n+1)
+			}
+		}
+		slugs[slug] = 0
+		bookmark := uniqueBookmark(bookmarkName(slug), used)
+		number := ""
+		if g.opts.NumberHeadings && !h.Attr.HasClass("unnumbered") {
+			number = headingNumber(g.numberFormats, counters, h.Level)
+		}
+		g.headings = append(g.headings, headingEntry{level: h.Level, text: text, slug: slug, bookmark: bookmark, number: number})
+	})
+}
+
+// headerXML 生成标题段落，标题文本由书签包裹以便文档内链接跳转，编号的标题引用多级列表
+func (g *generator) headerXML(h models.Header) string {
+	run := g.inlinesXML(h.Inlines)
+	numPr := ""
+	if g.headingsDone < len(g.headings) {
+		entry := g.headings[g.headingsDone]
+		g.headingsDone++
+		run = g.bookmarkXML(entry.bookmark, run)
+		if entry.number != "" {
+			numPr = fmt.Sprintf(`<w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr>`, h.Level-1, g.headingNumID)
+		}
+	}
+	return fmt.Sprintf(`<w:p><w:pPr><w:pStyle w:val="Heading%d"/>%s</w:pPr>%s</w:p>`, h.Level, numPr, run)
+}
+
+// headingBookmark 返回锚点对应的标题书签名
+func (g *generator) headingBookmark(slug string) string {
+	for _, entry := range g.headings {
+		if entry.slug == slug {
+			return entry.bookmark
+		}
+	}
+	g.warn("未找到链接指向的标题: #%s", slug)
+	return bookmarkName(slug)
+}
+
+// linkXML 生成超链接，#开头的地址指向文档内的标题，锚点按URL编码解码，其他地址作为外部关系
+func (g *generator) linkXML(l models.Link, rPr runProps) string {
+	attrs := ` w:history="1"`
+	if l.Title != "" {
+		attrs += ` w:tooltip="` + escapeXML(l.Title) + `"`
+	}
+	rPr.style = "Hyperlink"
+	content := g.runsXML(l.Content, rPr)
+	if strings.HasPrefix(l.URL, "#") {
+		slug := l.URL[1:]
+		if decoded, err := url.PathUnescape(slug); err == nil {
+			slug = decoded
+		}
+		return `<w:hyperlink w:anchor="` + g.headingBookmark(slug) + `"` + attrs + `>` + content + `</w:hyperlink>`
+	}
+	id, ok := g.hyperlinks[l.URL]
+	if !ok {
+		id = g.addRelationship(relTypeHyperlink, l.URL, true)
+		g.hyperlinks[l.URL] = id
+	}
+	return `<w:hyperlink r:id="` + id + `"` + attrs + `>` + content + `</w:hyperlink>`
+}
+
+// slugify 按GitHub的规则由标题文本生成锚点：转为小写，去掉标点，空格替换为连字符
+func slugify(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestLinkXML(t *testing.T) {
+	doc := models.Document{Blocks: []models.Block{
+		models.Paragraph{Inlines: []models.Inline{
+			models.Link{Content: []models.Inline{models.Text{Content: "Go"}}, URL: "https://go.dev/?a=1&b=2", Title: "Go 官网"},
+			models.Link{Content: []models.Inline{models.Bold{Content: []models.Inline{models.Text{Content: "再次"}}}}, URL: "https://go.dev/?a=1&b=2"},
+			models.Link{Content: []models.Inline{models.Text{Content: "跳转"}}, URL: "#快速开始"},
+			models.Link{Content: []models.Inline{models.Bold{Content: []models.Inline{models.Code{Content: "go run"}}}}, URL: "#快速开始"},
+			models.Link{Content: []models.Inline{models.Text{Content: "编码"}}, URL: "#%E5%BF%AB%E9%80%9F%E5%BC%80%E5%A7%8B"},
+		}},
+		models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "快速开始"}}},
+		models.Header{Level: 2, Inlines: []models.Inline{models.Text{Content: "快速开始"}}},
+	}}
+	g := newGenerator(Options{})
+	xml := g.documentXML(doc)
+
+	if !strings.Contains(xml, `<w:hyperlink r:id="rId2" w:history="1" w:tooltip="Go 官网"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t>Go</w:t></w:r></w:hyperlink>`) {
+		t.Errorf("外部链接生成错误，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:rPr><w:rStyle w:val="Hyperlink"/><w:b/><w:bCs/></w:rPr>`) {
+		t.Error("链接中的粗体文本应同时带有超链接样式")
+	}
+	if !strings.Contains(xml, `<w:rPr><w:rStyle w:val="Hyperlink"/><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:b/><w:bCs/></w:rPr><w:t xml:space="preserve">go run</w:t>`) {
+		t.Errorf("链接中的代码应保留超链接样式，字体位于粗体之前，实际XML为:\n%s", xml)
+	}
+	if len(g.rels) != 2 || !g.rels[1].External || g.rels[1].Target != "https://go.dev/?a=1&b=2" {
+		t.Errorf("相同地址的链接应只添加一个外部关系，实际为%+v", g.rels)
+	}
+	if !strings.Contains(relationshipsXML(g.rels), `Target="https://go.dev/?a=1&amp;b=2" TargetMode="External"`) {
+		t.Error("外部关系应标记TargetMode并转义地址")
+	}
+	if strings.Count(xml, `<w:hyperlink w:anchor="快速开始" w:history="1">`) != 3 {
+		t.Error("指向标题的链接应使用书签锚点，URL编码的锚点应先解码")
+	}
+	if !strings.Contains(xml, `<w:bookmarkStart w:id="0" w:name="快速开始"/><w:r><w:t>快速开始</w:t></w:r><w:bookmarkEnd w:id="0"/>`) {
+		t.Error("标题应被书签包裹")
+	}
+	if !strings.Contains(xml, `w:name="快速开始_1"`) {
+		t.Error("重复的标题应生成不同的书签")
+	}
+
+	// 找不到链接指向的标题时给出警告
+	var warnings []string
+	g = newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }})
+	if xml := g.linkXML(models.Link{Content: []models.Inline{models.Text{Content: "跳转"}}, URL: "#不存在"}, runProps{}); !strings.Contains(xml, `w:anchor="不存在"`) {
+		t.Errorf("找不到标题时仍应按锚点生成链接，实际为'%s'", xml)
+	}
+	if len(warnings) != 1 || warnings[0] != "未找到链接指向的标题: #不存在" {
+		t.Errorf("找不到链接指向的标题时应给出警告，实际为%q", warnings)
+	}
+}
+
+func TestHeadingAndFigureBookmarks(t *testing.T) {
+	doc := models.Document{Blocks: []models.Block{
+		models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "架构"}}, Attr: models.Attributes{ID: "fig_arch"}},
+		models.Paragraph{Inlines: []models.Inline{
+			models.Reference{ID: "fig:arch"},
+			models.Link{Content: []models.Inline{models.Text{Content: "章节"}}, URL: "#fig_arch"},
+		}},
+		models.Figure{Image: models.Image{Src: "arch.png", Alt: "架构图", Attr: models.Attributes{ID: "fig:arch"}}, Caption: []models.Inline{models.Text{Content: "架构图"}}},
+	}}
+	xml := newGenerator(Options{}).documentXML(doc)
+	if !strings.Contains(xml, `w:name="fig_arch_1"/><w:r><w:t>架构</w:t>`) || !strings.Contains(xml, `<w:hyperlink w:anchor="fig_arch_1"`) {
+		t.Errorf("标题的书签不应与图的书签重名，链接应指向标题:\n%s", xml)
+	}
+	if strings.Count(xml, `w:name="fig_arch"`) != 1 || !strings.Contains(xml, `REF fig_arch \h`) {
+		t.Errorf("图的交叉引用应指向图的书签:\n%s", xml)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	testCases := map[string]string{
+		"Hello, World!":    "hello-world",
+		"  API v2.0 参考  ":  "api-v20-参考",
+		"snake_case-Title": "snake_case-title",
+	}
+	for text, expected := range testCases {
+		if got := slugify(text); got != expected {
+			t.Errorf("slugify(%q) 期望为'%s'，实际为'%s'", text, expected, got)
+		}
+	}
+}
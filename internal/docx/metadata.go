@@ -0,0 +1,148 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"goffice/internal/models"
+)
+
+// coreXML 生成docProps/core.xml，记录标题、作者、关键词等文档属性，创建和修改时间为now
+func coreXML(meta models.Metadata, now time.Time) string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties
+    xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:dcterms="http://purl.org/dc/terms/"
+    xmlns:dcmitype="http://purl.org/dc/dcmitype/"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`
+	property := func(name, value string) {
+		if value != "" {
+			xml += "\n    <" + name + ">" + escapeXML(value) + "</" + name + ">"
+		}
+	}
+	property("dc:title", meta.Title)
+	property("dc:subject", meta.Subtitle)
+	property("dc:creator", strings.Join(meta.Authors, "; "))
+	property("cp:keywords", strings.Join(meta.Keywords, ", "))
+	property("dc:description", meta.Abstract)
+	property("dc:language", meta.Lang)
+	if len(meta.Authors) > 0 {
+		property("cp:lastModifiedBy", meta.Authors[0])
+	}
+	property("cp:revision", "1")
+	// 时间使用W3CDTF格式的UTC时间
+	timestamp := now.UTC().Format("2006-01-02T15:04:05Z")
+	xml += "\n    " + `<dcterms:created xsi:type="dcterms:W3CDTF">` + timestamp + `</dcterms:created>`
+	xml += "\n    " + `<dcterms:modified xsi:type="dcterms:W3CDTF">` + timestamp + `</dcterms:modified>`
+	return xml + "\n</cp:coreProperties>"
+}
+
+// appXML 生成docProps/app.xml，记录生成文档的应用程序和文档的统计信息，单位和公司来自元数据
+func appXML(stats documentStats, meta models.Metadata) string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+    xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">`
+	property := func(name, value string) {
+		if value != "" {
+			xml += "\n    <" + name + ">" + escapeXML(value) + "</" + name + ">"
+		}
+	}
+	property("Template", "Normal.dotm")
+	property("TotalTime", "0")
+	property("Pages", strconv.Itoa(stats.pages))
+	property("Words", strconv.Itoa(stats.words))
+	property("Characters", strconv.Itoa(stats.characters))
+	property("Application", "goffice")
+	property("DocSecurity", "0")
+	property("Lines", strconv.Itoa(stats.lines))
+	property("Paragraphs", strconv.Itoa(stats.paragraphs))
+	property("ScaleCrop", "false")
+	property("Manager", meta.Extra["manager"])
+	property("Company", meta.Extra["company"])
+	property("LinksUpToDate", "false")
+	property("CharactersWithSpaces", strconv.Itoa(stats.charactersWithSpaces))
+	property("SharedDoc", "false")
+	property("HyperlinksChanged", "false")
+	return xml + "\n</Properties>"
+}
+
+// layoutFields 元数据中控制版式或写入app.xml的字段，不作为自定义属性
+var layoutFields = map[string]bool{"columns": true, "column-gap": true, "company": true, "manager": true}
+
+// customXML 生成docProps/custom.xml，将元数据中未识别的字段写为自定义属性；没有这样的字段时返回空字符串。
+// 取值为布尔值、整数或小数时使用对应的类型，其他取值为文本
+func customXML(meta models.Metadata) string {
+	var keys []string
+	for key := range meta.Extra {
+		if !layoutFields[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+    xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">`
+	for i, key := range keys {
+		// pid从2开始，0和1由系统保留
+		xml += "\n    " + fmt.Sprintf(`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="%d" name="%s">`, i+2, escapeXML(key)) +
+			customValueXML(meta.Extra[key]) + `</property>`
+	}
+	return xml + "\n</Properties>"
+}
+
+// decimalPattern 匹配xsd:double可以表示的小数，如 3.14、-2.5e3
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// customValueXML 按取值的类型生成自定义属性的值
+func customValueXML(value string) string {
+	if value == "true" || value == "false" {
+		return `<vt:bool>` + value + `</vt:bool>`
+	}
+	if n, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return `<vt:i4>` + strconv.FormatInt(n, 10) + `</vt:i4>`
+	}
+	if decimalPattern.MatchString(value) {
+		return `<vt:r8>` + value + `</vt:r8>`
+	}
+	return `<vt:lpwstr>` + escapeXML(value) + `</vt:lpwstr>`
+}
+
+// titleBlockXML 生成文档开头的标题、副标题、作者、日期和摘要
+func titleBlockXML(meta models.Metadata) string {
+	paragraph := func(style, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>` + textRunXML(runProps{}, text) + `</w:p>`
+	}
+	xml := ""
+	if meta.Title != "" {
+		xml += paragraph("Title", meta.Title)
+	}
+	if meta.Subtitle != "" {
+		xml += paragraph("Subtitle", meta.Subtitle)
+	}
+	for _, author := range meta.Authors {
+		xml += paragraph("Author", author)
+	}
+	if meta.Date != "" {
+		xml += paragraph("Date", meta.Date)
+	}
+	// 摘要中的空行分隔段落，其余换行来自YAML的字面块（|），保留为段落内的换行
+	for _, text := range strings.Split(meta.Abstract, "\n\n") {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		var lines []string
+		for _, line := range strings.Split(strings.Trim(text, "\n"), "\n") {
+			lines = append(lines, textRunXML(runProps{}, strings.TrimSpace(line)))
+		}
+		xml += `<w:p><w:pPr><w:pStyle w:val="Abstract"/></w:pPr>` + strings.Join(lines, `<w:r><w:br/></w:r>`) + `</w:p>`
+	}
+	return xml
+}
@@ -0,0 +1,130 @@
+package docx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"goffice/internal/models"
+)
+
+func TestMetadataXML(t *testing.T) {
+	meta := models.Metadata{
+		Title:    "A & B",
+		Subtitle: "副标题",
+		Authors:  []string{"张三", "李四"},
+		Date:     "2024-05-01",
+		Abstract: "第一段\n续行\n\n第二段",
+		Keywords: []string{"Go", "DOCX"},
+		Lang:     "zh-CN",
+	}
+
+	core := coreXML(meta, time.Date(2024, 5, 1, 8, 30, 0, 0, time.FixedZone("CST", 8*3600)))
+	for _, want := range []string{
+		`<dc:title>A &amp; B</dc:title>`,
+		`<dc:creator>张三; 李四</dc:creator>`,
+		`<cp:keywords>Go, DOCX</cp:keywords>`,
+		`<dc:language>zh-CN</dc:language>`,
+		`<cp:lastModifiedBy>张三</cp:lastModifiedBy>`,
+		`<dcterms:created xsi:type="dcterms:W3CDTF">2024-05-01T00:30:00Z</dcterms:created>`,
+		`<dcterms:modified xsi:type="dcterms:W3CDTF">2024-05-01T00:30:00Z</dcterms:modified>`,
+	} {
+		if !strings.Contains(core, want) {
+			t.Errorf("core.xml缺少%s", want)
+		}
+	}
+	if strings.Contains(coreXML(models.Metadata{}, time.Now()), "<dc:title>") {
+		t.Error("空的属性不应写入core.xml")
+	}
+
+	xml := newGenerator(Options{TitleBlock: true}).documentXML(models.Document{Metadata: meta})
+	for _, want := range []string{
+		`<w:pStyle w:val="Title"/></w:pPr><w:r><w:t>A &amp; B</w:t>`,
+		`<w:pStyle w:val="Author"/></w:pPr><w:r><w:t>李四</w:t>`,
+		`<w:pStyle w:val="Abstract"/></w:pPr><w:r><w:t>第一段</w:t></w:r><w:r><w:br/></w:r><w:r><w:t>续行</w:t></w:r></w:p>`,
+		`<w:pStyle w:val="Abstract"/></w:pPr><w:r><w:t>第二段</w:t>`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("标题块缺少%s", want)
+		}
+	}
+	if strings.Contains(GenerateDocumentXML(models.Document{Metadata: meta}), "Title") {
+		t.Error("默认不应生成标题块")
+	}
+}
+
+func TestDocumentProperties(t *testing.T) {
+	t.Run("自定义属性", func(t *testing.T) {
+		custom := customXML(models.Metadata{Extra: map[string]string{
+			"部门": "研发&测试", "version": "3", "draft": "true", "ratio": "0.75", "hex": "0x1p-2", "columns": "2", "company": "某公司",
+		}})
+		for _, want := range []string{
+			`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="draft"><vt:bool>true</vt:bool></property>`,
+			`pid="3" name="hex"><vt:lpwstr>0x1p-2</vt:lpwstr>`,
+			`pid="4" name="ratio"><vt:r8>0.75</vt:r8>`,
+			`pid="5" name="version"><vt:i4>3</vt:i4>`,
+			`pid="6" name="部门"><vt:lpwstr>研发&amp;测试</vt:lpwstr>`,
+		} {
+			if !strings.Contains(custom, want) {
+				t.Errorf("custom.xml缺少 %s:\n%s", want, custom)
+			}
+		}
+		if strings.Contains(custom, `name="columns"`) || strings.Contains(custom, `name="company"`) {
+			t.Error("版式字段和公司不应写为自定义属性")
+		}
+		if customXML(models.Metadata{Extra: map[string]string{"columns": "2"}}) != "" {
+			t.Error("没有自定义字段时不应生成custom.xml")
+		}
+	})
+
+	t.Run("统计信息", func(t *testing.T) {
+		doc := models.Document{
+			Metadata: models.Metadata{Title: "标题"},
+			Blocks: []models.Block{
+				models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "第一章"}}},
+				models.Paragraph{Inlines: []models.Inline{models.Text{Content: "Hello world，你好。"}}},
+				models.PageBreak{},
+				models.Paragraph{Inlines: []models.Inline{models.Text{Content: strings.Repeat("字", 100)}}},
+			},
+			Footnotes: []models.Footnote{{ID: "1", Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "注释"}}}}}},
+		}
+		stats := newGenerator(Options{TitleBlock: true}).documentStats(doc)
+		want := documentStats{pages: 2, words: 2 + 3 + 2 + 4 + 100 + 2, characters: 2 + 3 + 14 + 100 + 2, charactersWithSpaces: 2 + 3 + 15 + 100 + 2, paragraphs: 5, lines: 1 + 1 + 1 + 3 + 1}
+		if stats != want {
+			t.Errorf("统计信息错误: %+v，应为 %+v", stats, want)
+		}
+		// 版心高度不足一行时每页按一行计，脚注的一行不计入页数
+		g := newGenerator(Options{Page: PageSetup{Size: "100x10mm", Margins: "4.8mm,1mm"}})
+		if g.err != nil {
+			t.Fatal(g.err)
+		}
+		if small := g.documentStats(doc); small.pages != 7 || small.lines != 8 {
+			t.Errorf("版心很小时的统计信息错误: %+v", small)
+		}
+		app := appXML(stats, models.Metadata{Extra: map[string]string{"company": "某公司"}})
+		for _, want := range []string{"<Pages>2</Pages>", "<Words>113</Words>", "<Application>goffice</Application>", "<Company>某公司</Company>", "<CharactersWithSpaces>122</CharactersWithSpaces>"} {
+			if !strings.Contains(app, want) {
+				t.Errorf("app.xml缺少 %s", want)
+			}
+		}
+	})
+
+	t.Run("包关系", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.docx")
+		doc := models.Document{Metadata: models.Metadata{Title: "标题", Extra: map[string]string{"project": "goffice"}}}
+		if err := CreateDOCXWithOptions(doc, out, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		files := readDOCX(t, out)
+		if !strings.Contains(files["_rels/.rels"], `<Relationship Id="rId4" Type="`+relTypeCustomProperties+`" Target="docProps/custom.xml"/>`) {
+			t.Errorf("_rels/.rels缺少自定义属性:\n%s", files["_rels/.rels"])
+		}
+		if !strings.Contains(files["[Content_Types].xml"], `<Override PartName="/docProps/custom.xml" ContentType="`+contentTypeCustom+`"/>`) {
+			t.Error("[Content_Types].xml缺少custom.xml")
+		}
+		if !strings.Contains(files["docProps/custom.xml"], `name="project"><vt:lpwstr>goffice</vt:lpwstr>`) {
+			t.Error("custom.xml内容错误")
+		}
+	})
+}
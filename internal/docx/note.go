@@ -0,0 +1,141 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// noteKind 描述脚注或尾注部件中使用的元素和样式名称
+type noteKind struct {
+	part        string // 部件名，不含扩展名
+	element     string // 单条注释的元素名
+	reference   string // 正文中引用注释的元素名
+	mark        string // 注释内容开头的编号标记元素名
+	textStyle   string // 注释内容的段落样式
+	refStyle    string // 注释编号的字符样式
+	relType     string
+	contentType string
+}
+
+var (
+	footnoteKind = noteKind{"footnotes", "footnote", "footnoteReference", "footnoteRef", "FootnoteText", "FootnoteReference", relTypeFootnotes, contentTypeFootnotes}
+	endnoteKind  = noteKind{"endnotes", "endnote", "endnoteReference", "endnoteRef", "EndnoteText", "EndnoteReference", relTypeEndnotes, contentTypeEndnotes}
+)
+
+// noteKind 返回脚注的生成方式：页下脚注或尾注
+func (o Options) noteKind() noteKind {
+	if o.Endnotes {
+		return endnoteKind
+	}
+	return footnoteKind
+}
+
+// noteEntry 记录正文中引用的一条脚注，同一脚注被多次引用时共用一条注释
+type noteEntry struct {
+	id     int    // 注释编号，从1开始，-1和0留给分隔线
+	label  string // 脚注标签
+	blocks []models.Block
+}
+
+// collectFootnotes 记录脚注定义，供引用时查找
+func (g *generator) collectFootnotes(footnotes []models.Footnote) {
+	g.footnotes, g.notes = map[string][]models.Block{}, nil
+	for _, f := range footnotes {
+		g.footnotes[f.ID] = f.Blocks
+	}
+}
+
+// noteReferenceXML 生成正文中的脚注引用。脚注和批注内容中的引用不被Word支持，被引用脚注的文本放在括号中输出；
+// 未定义的脚注按原文输出
+func (g *generator) noteReferenceXML(r models.FootnoteReference) string {
+	blocks, ok := g.footnotes[r.ID]
+	if !ok {
+		g.warn("未找到脚注的定义: [^%s]", r.ID)
+		return textRunXML(runProps{}, "[^"+r.ID+"]")
+	}
+	if g.inNote || g.inComment {
+		g.warn("脚注和批注中的脚注引用不被Word支持，脚注内容放在括号中输出")
+		text := noteText(blocks)
+		if text == "" {
+			return ""
+		}
+		return textRunXML(runProps{}, "("+text+")")
+	}
+	kind := g.opts.noteKind()
+	return fmt.Sprintf(`<w:r><w:rPr><w:rStyle w:val="%s"/></w:rPr><w:%s w:id="%d"/></w:r>`, kind.refStyle, kind.reference, g.noteID(r.ID, blocks))
+}
+
+// noteID 返回脚注的注释编号，首次引用时按引用顺序分配
+func (g *generator) noteID(label string, blocks []models.Block) int {
+	for _, entry := range g.notes {
+		if entry.label == label {
+			return entry.id
+		}
+	}
+	entry := noteEntry{id: len(g.notes) + 1, label: label, blocks: blocks}
+	g.notes = append(g.notes, entry)
+	return entry.id
+}
+
+// noteText 返回脚注内容的纯文本，各段之间以空格分隔
+func noteText(blocks []models.Block) string {
+	var texts []string
+	walkBlocks(blocks, func(block models.Block) {
+		if p, ok := block.(models.Paragraph); ok {
+			if text := strings.TrimSpace(models.PlainText(p.Inlines)); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	})
+	return strings.Join(texts, " ")
+}
+
+// notesXML 生成footnotes.xml或endnotes.xml，包括Word要求的分隔线和延续分隔线
+func (g *generator) notesXML() string {
+	kind := g.opts.noteKind()
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:` + kind.part + `
+    xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+    xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"
+    xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+    xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing">`
+	separator := `<w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:r><w:%s/></w:r></w:p>`
+	xml += fmt.Sprintf(`<w:%s w:type="separator" w:id="-1">`+separator+`</w:%s>`, kind.element, "separator", kind.element)
+	xml += fmt.Sprintf(`<w:%s w:type="continuationSeparator" w:id="0">`+separator+`</w:%s>`, kind.element, "continuationSeparator", kind.element)
+
+	g.inNote = true
+	defer func() { g.inNote = false }()
+	mark := fmt.Sprintf(`<w:r><w:rPr><w:rStyle w:val="%s"/></w:rPr><w:%s/></w:r><w:r><w:t xml:space="preserve"> </w:t></w:r>`, kind.refStyle, kind.mark)
+	pPr := `<w:pStyle w:val="` + kind.textStyle + `"/>`
+	for _, entry := range g.notes {
+		xml += fmt.Sprintf(`<w:%s w:id="%d">`, kind.element, entry.id)
+		blocks := entry.blocks
+		// 编号标记放在第一个段落的开头，内容不以段落开头时单独成段
+		if first, ok := firstParagraph(blocks); ok {
+			xml += `<w:p><w:pPr>` + pPr + `</w:pPr>` + mark + g.inlinesXML(first.Inlines) + `</w:p>`
+			blocks = blocks[1:]
+		} else {
+			xml += `<w:p><w:pPr>` + pPr + `</w:pPr>` + mark + `</w:p>`
+		}
+		for _, block := range blocks {
+			if p, ok := block.(models.Paragraph); ok {
+				xml += g.paragraphXML(p, pPr)
+			} else {
+				xml += g.blockXML(block)
+			}
+		}
+		xml += `</w:` + kind.element + `>`
+	}
+	return xml + `</w:` + kind.part + `>`
+}
+
+// firstParagraph 返回以段落开头的块列表中的第一个段落
+func firstParagraph(blocks []models.Block) (models.Paragraph, bool) {
+	if len(blocks) == 0 {
+		return models.Paragraph{}, false
+	}
+	p, ok := blocks[0].(models.Paragraph)
+	return p, ok
+}
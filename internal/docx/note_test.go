@@ -0,0 +1,116 @@
+package docx
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestNotesXML(t *testing.T) {
+	doc := models.Document{
+		Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{
+				models.Text{Content: "正文"},
+				models.FootnoteReference{ID: "1"},
+				models.FootnoteReference{ID: "1"},
+				models.FootnoteReference{ID: "未定义"},
+			}},
+		},
+		Footnotes: []models.Footnote{{ID: "1", Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{
+				models.Link{Content: []models.Inline{models.Text{Content: "来源"}}, URL: "https://go.dev"},
+			}},
+			models.Paragraph{Inlines: []models.Inline{models.Text{Content: "第二段"}}},
+		}}},
+	}
+
+	t.Run("脚注", func(t *testing.T) {
+		var warnings []string
+		g := newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }})
+		xml := g.documentXML(doc)
+		if strings.Count(xml, `<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="1"/></w:r>`) != 2 || len(g.notes) != 1 {
+			t.Errorf("多次引用同一脚注应共用一个编号，实际XML为:\n%s", xml)
+		}
+		if !strings.Contains(xml, `<w:t>[^未定义]</w:t>`) {
+			t.Error("未定义的脚注应输出为文本")
+		}
+		if len(warnings) != 1 || warnings[0] != "未找到脚注的定义: [^未定义]" {
+			t.Errorf("未定义的脚注应给出警告，实际为%q", warnings)
+		}
+		var notes string
+		rels := g.withPart(func() { notes = g.notesXML() })
+		for _, want := range []string{
+			`<w:footnote w:type="separator" w:id="-1">`,
+			`<w:footnote w:type="continuationSeparator" w:id="0">`,
+			`<w:footnote w:id="1"><w:p><w:pPr><w:pStyle w:val="FootnoteText"/></w:pPr><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteRef/></w:r>`,
+			`<w:hyperlink r:id="rId1"`,
+		} {
+			if !strings.Contains(notes, want) {
+				t.Errorf("脚注部件缺少%s，实际为:\n%s", want, notes)
+			}
+		}
+		if len(rels) != 1 || len(g.rels) != 1 {
+			t.Errorf("脚注中的链接应添加到脚注部件的关系中，脚注关系%+v，文档关系%+v", rels, g.rels)
+		}
+	})
+
+	t.Run("脚注中的脚注引用", func(t *testing.T) {
+		g := newGenerator(Options{})
+		g.documentXML(models.Document{
+			Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.FootnoteReference{ID: "a"}}}},
+			Footnotes: []models.Footnote{
+				{ID: "a", Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "外层"}, models.FootnoteReference{ID: "inline 1"}}}}},
+				{ID: "inline 1", Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "内层"}}}}},
+			},
+		})
+		notes := g.notesXML()
+		if !strings.Contains(notes, `<w:r><w:t>外层</w:t></w:r><w:r><w:t>(内层)</w:t></w:r>`) || strings.Contains(notes, "inline 1") {
+			t.Errorf("脚注中引用的脚注应以括号中的文本输出，实际为:\n%s", notes)
+		}
+	})
+
+	t.Run("尾注", func(t *testing.T) {
+		g := newGenerator(Options{Endnotes: true})
+		xml := g.documentXML(doc)
+		notes := g.notesXML()
+		if !strings.Contains(xml, `<w:endnoteReference w:id="1"/>`) || !strings.Contains(notes, `<w:endnotes`) ||
+			!strings.Contains(notes, `<w:endnoteRef/>`) || !strings.Contains(g.settingsXML(), `<w:endnotePr><w:endnote w:id="-1"/>`) {
+			t.Errorf("尾注生成错误:\n%s\n%s", xml, notes)
+		}
+	})
+
+	t.Run("写入DOCX包", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "notes.docx")
+		if err := CreateDOCX(doc, filename); err != nil {
+			t.Fatal(err)
+		}
+		r, err := zip.OpenReader(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		files := map[string]string{}
+		for _, f := range r.File {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(data)
+		}
+		if _, ok := files["word/footnotes.xml"]; !ok {
+			t.Fatal("缺少word/footnotes.xml")
+		}
+		if !strings.Contains(files["word/_rels/footnotes.xml.rels"], `Target="https://go.dev"`) {
+			t.Error("脚注部件应有自己的关系文件")
+		}
+		if !strings.Contains(files["word/_rels/document.xml.rels"], `Target="footnotes.xml"`) {
+			t.Error("文档关系中缺少脚注部件")
+		}
+		if !strings.Contains(files["[Content_Types].xml"], `<Override PartName="/word/footnotes.xml"`) {
+			t.Error("内容类型中缺少脚注部件")
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// numberingLevel 描述多级编号中一级的格式
+type numberingLevel struct {
+	numFmt  string // 数字格式，如 decimal、chineseCounting
+	lvlText string // 编号文本，%1 表示第一级的序号
+	suffix  string // 编号与标题文本之间的字符：space、tab 或 nothing
+}
+
+// headingNumberFormats 标题各级的编号格式，与Heading1到Heading9样式对应
+var headingNumberFormats = []numberingLevel{
+	{"decimal", "%1", "space"},
+	{"decimal", "%1.%2", "space"},
+	{"decimal", "%1.%2.%3", "space"},
+	{"decimal", "%1.%2.%3.%4", "space"},
+	{"decimal", "%1.%2.%3.%4.%5", "space"},
+	{"decimal", "%1.%2.%3.%4.%5.%6", "space"},
+	{"decimal", "%1.%2.%3.%4.%5.%6.%7", "space"},
+	{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8", "space"},
+	{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8.%9", "space"},
+}
+
+// headingNumber 将level级标题计入计数器并返回其编号文本，下级计数器随之重置。
+// 编号文本中引用的各级序号按该级的数字格式显示
+func headingNumber(formats []numberingLevel, counters []int, level int) string {
+	if level < 1 || level > len(counters) || level > len(formats) {
+		return ""
+	}
+	counters[level-1]++
+	for i := level; i < len(counters); i++ {
+		counters[i] = 0
+	}
+	text := formats[level-1].lvlText
+	for i := level; i >= 1; i-- {
+		text = strings.ReplaceAll(text, "%"+strconv.Itoa(i), formatNumber(counters[i-1], formats[i-1].numFmt))
+	}
+	return text
+}
+
+// formatNumber 按numFmt的格式显示序号，不支持的格式按阿拉伯数字显示
+func formatNumber(n int, numFmt string) string {
+	switch numFmt {
+	case "chineseCounting":
+		return chineseNumber(n)
+	case "decimalEnclosedCircle":
+		if n >= 1 && n <= 20 {
+			return string(rune('①' + n - 1))
+		}
+	case "lowerLetter":
+		if n >= 1 {
+			// Word在字母用完后重复字母，如 aa、bb
+			return strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+		}
+	case "lowerRoman":
+		return romanNumber(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// chineseNumber 将1到99999的整数写成中文小写数字，如 十二、一百零五
+func chineseNumber(n int) string {
+	if n <= 0 || n > 99999 {
+		return strconv.Itoa(n)
+	}
+	digits := []rune("零一二三四五六七八九")
+	units := []string{"", "十", "百", "千", "万"}
+	var b strings.Builder
+	s := strconv.Itoa(n)
+	zero := false
+	for i, c := range s {
+		d := int(c - '0')
+		unit := len(s) - 1 - i
+		if d == 0 {
+			zero = true
+			continue
+		}
+		if zero {
+			b.WriteRune('零')
+			zero = false
+		}
+		// 十到十九省略开头的“一”
+		if !(d == 1 && unit == 1 && i == 0) {
+			b.WriteRune(digits[d])
+		}
+		b.WriteString(units[unit])
+	}
+	return b.String()
+}
+
+// romanNumber 将整数写成小写罗马数字
+func romanNumber(n int) string {
+	if n <= 0 || n >= 4000 {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"m", "cm", "d", "cd", "c", "xc", "l", "xl", "x", "ix", "v", "iv", "i"}
+	var b strings.Builder
+	for i, v := range values {
+		for ; n >= v; n -= v {
+			b.WriteString(symbols[i])
+		}
+	}
+	return b.String()
+}
+
+// hasNumberedHeadings 判断文档中是否有需要编号的标题
+func (g *generator) hasNumberedHeadings() bool {
+	for _, entry := range g.headings {
+		if entry.number != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// numberingXML 生成numbering.xml，定义与标题样式关联的多级编号
+func (g *generator) numberingXML() string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+    ` + g.headingAbstractNumXML(0) + `
+    ` + fmt.Sprintf(`<w:num w:numId="%d"><w:abstractNumId w:val="0"/></w:num>`, g.headingNumID) + `
+</w:numbering>`
+}
+
+// headingAbstractNumXML 生成标题多级编号的抽象定义
+func (g *generator) headingAbstractNumXML(id int) string {
+	xml := fmt.Sprintf(`<w:abstractNum w:abstractNumId="%d"><w:multiLevelType w:val="multilevel"/>`, id)
+	for i, level := range g.numberFormats {
+		xml += fmt.Sprintf(`<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:pStyle w:val="Heading%d"/>`+
+			`<w:suff w:val="%s"/><w:lvlText w:val="%s"/><w:lvlJc w:val="left"/></w:lvl>`, i, level.numFmt, i+1, level.suffix, escapeXML(level.lvlText))
+	}
+	return xml + `</w:abstractNum>`
+}
+
+// mergeNumbering 将标题多级编号加入参考文档的numbering.xml，使用其中未占用的编号。
+// 抽象定义须位于所有w:num之前，w:num须位于w:numIdMacAtCleanup之前
+func (g *generator) mergeNumbering(numbering string) string {
+	abstractID := maxAttr(numbering, `w:abstractNumId="`) + 1
+	abstract := g.headingAbstractNumXML(abstractID)
+	num := fmt.Sprintf(`<w:num w:numId="%d"><w:abstractNumId w:val="%d"/></w:num>`, g.headingNumID, abstractID)
+	if i := strings.Index(numbering, "<w:num "); i != -1 {
+		numbering = numbering[:i] + abstract + numbering[i:]
+	} else {
+		num = abstract + num
+	}
+	end := strings.Index(numbering, "<w:numIdMacAtCleanup")
+	if end == -1 {
+		end = strings.LastIndex(numbering, "</w:numbering>")
+	}
+	if end == -1 {
+		return numbering
+	}
+	return numbering[:end] + num + numbering[end:]
+}
+
+// maxAttr 返回XML中给定属性的最大整数值，没有该属性时返回-1
+func maxAttr(xml, attr string) int {
+	max := -1
+	for rest := xml; ; {
+		i := strings.Index(rest, attr)
+		if i == -1 {
+			return max
+		}
+		rest = rest[i+len(attr):]
+		if end := strings.IndexByte(rest, '"'); end != -1 {
+			if n, err := strconv.Atoi(rest[:end]); err == nil && n > max {
+				max = n
+			}
+		}
+	}
+}
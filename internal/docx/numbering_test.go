@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestHeadingNumbering(t *testing.T) {
+	heading := func(level int, text string, attr models.Attributes) models.Header {
+		return models.Header{Level: level, Inlines: []models.Inline{models.Text{Content: text}}, Attr: attr}
+	}
+	doc := models.Document{Blocks: []models.Block{
+		models.TableOfContents{},
+		heading(1, "简介", models.Attributes{ID: "intro"}),
+		heading(2, "背景", models.Attributes{}),
+		heading(2, "目标", models.Attributes{}),
+		heading(1, "致谢", models.Attributes{Classes: []string{"unnumbered"}}),
+		heading(1, "设计", models.Attributes{}),
+		heading(2, "架构", models.Attributes{}),
+	}}
+	g := newGenerator(Options{NumberHeadings: true})
+	xml := g.documentXML(doc)
+
+	var numbers []string
+	for _, entry := range g.headings {
+		numbers = append(numbers, entry.number)
+	}
+	if strings.Join(numbers, ",") != "1,1.1,1.2,,2,2.1" {
+		t.Errorf("标题编号错误，实际为%q", numbers)
+	}
+	if g.headings[0].bookmark != "intro" {
+		t.Errorf("{#id} 应作为标题的书签，实际为%s", g.headings[0].bookmark)
+	}
+	if !strings.Contains(xml, `<w:pStyle w:val="Heading2"/><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr>`) {
+		t.Errorf("编号的标题应引用多级列表，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:pStyle w:val="Heading1"/></w:pPr>`) {
+		t.Error("不编号的标题不应引用多级列表")
+	}
+	if !strings.Contains(xml, `<w:t xml:space="preserve">2.1 架构</w:t>`) {
+		t.Error("目录中的标题应带编号")
+	}
+	if !g.hasNumberedHeadings() || newGenerator(Options{}).hasNumberedHeadings() {
+		t.Error("只有启用编号时才需要生成numbering.xml")
+	}
+
+	numbering := newGenerator(Options{}).numberingXML()
+	if !strings.Contains(numbering, `<w:lvl w:ilvl="2"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:pStyle w:val="Heading3"/><w:suff w:val="space"/><w:lvlText w:val="%1.%2.%3"/>`) {
+		t.Errorf("多级编号应与标题样式关联，实际为:\n%s", numbering)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		n      int
+		numFmt string
+		want   string
+	}{
+		{3, "decimal", "3"},
+		{1, "chineseCounting", "一"},
+		{10, "chineseCounting", "十"},
+		{12, "chineseCounting", "十二"},
+		{20, "chineseCounting", "二十"},
+		{105, "chineseCounting", "一百零五"},
+		{1010, "chineseCounting", "一千零一十"},
+		{2, "decimalEnclosedCircle", "②"},
+		{28, "lowerLetter", "bb"},
+		{14, "lowerRoman", "xiv"},
+	}
+	for _, c := range cases {
+		if got := formatNumber(c.n, c.numFmt); got != c.want {
+			t.Errorf("formatNumber(%d, %s) = %s，应为 %s", c.n, c.numFmt, got, c.want)
+		}
+	}
+}
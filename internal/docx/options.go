@@ -0,0 +1,81 @@
+package docx
+
+// Options 控制DOCX文件的生成
+type Options struct {
+	ResourceDir string // 解析图片等相对路径时使用的目录，通常为输入文件所在目录
+	FigureLabel string // 图题注的前缀，默认为 "Figure"
+	Endnotes    bool   // 将脚注作为尾注放在文档末尾
+	TitleBlock  bool   // 在文档开头生成元数据中的标题、作者、日期和摘要
+	TOC         bool   // 在正文开头插入目录
+
+	NumberHeadings bool   // 为标题添加 1、1.1、1.1.1 形式的多级编号
+	ReferenceDoc   string // 参考DOCX文件，复用其样式、主题、编号、设置、字体表、页眉页脚和页面设置
+
+	Preset string // 版式预设，如 gongwen 为GB/T 9704公文格式，设置页面、网格、字体字号和标题编号并启用标题编号
+	Fonts  Fonts  // 正文和标题的字体，未设置的字段使用默认字体或预设的字体
+	Lang   string // 文档语言，如 zh-CN、en-US，覆盖元数据中的 lang
+	Page   PageSetup
+
+	HeaderFooter HeaderFooter // 页眉和页脚
+
+	Columns   int    // 正文的栏数，标题信息和目录不分栏；为0时使用元数据中的 columns
+	ColumnGap string // 栏间距，如 8mm；为空时使用元数据中的 column-gap
+
+	CommentAuthor string // 批注的作者，默认为 Reviewer
+	CommentDate   string // 批注的日期，如 2024-05-01 或 2024-05-01T14:30:00；为空时使用生成文档的时间
+
+	Callouts map[string]CalloutStyle // 按提示类型覆盖提示块的外观，键为小写的类型名；命令行没有对应的选项
+
+	Warn func(message string) // 接收生成过程中的警告，如无法嵌入的图片，为nil时忽略警告
+}
+
+// Fonts 指定西文和东亚文字使用的字体
+type Fonts struct {
+	Latin            string // 正文的西文字体，默认为 Times New Roman
+	EastAsian        string // 正文的中文字体，默认为宋体
+	HeadingLatin     string // 标题的西文字体，默认与正文相同
+	HeadingEastAsian string // 标题的中文字体，默认为黑体
+}
+
+// PageSetup 页面设置，未设置的字段使用预设、参考文档或默认的A4纵向页面。
+// 长度可带单位 mm、cm、in、pt，没有单位时按毫米计
+type PageSetup struct {
+	Size        string // 纸张大小：A3、A4、A5、B5、Letter、Legal，或 宽x高 形式的自定义尺寸，如 184x260mm
+	Orientation string // 纸张方向：portrait 或 landscape
+	Margins     string // 页边距：一个值用于四边，两个值依次为上下和左右，四个值依次为上、右、下、左
+	Gutter      string // 装订线宽度
+	Header      string // 页眉距纸张上边缘的距离
+	Footer      string // 页脚距纸张下边缘的距离
+}
+
+// HeaderFooter 页眉页脚的内容模板。模板中的 {title}、{author}、{date} 替换为元数据，{page}、{pages} 替换为页码和总页数域；
+// 以 | 分隔的两部分或三部分分别左对齐、（居中、）右对齐，只有一部分时居中
+type HeaderFooter struct {
+	Header      string // 页眉，首页不同或奇偶页不同时用于奇数页
+	Footer      string // 页脚
+	FirstHeader string // 首页的页眉
+	FirstFooter string // 首页的页脚
+	EvenHeader  string // 偶数页的页眉
+	EvenFooter  string // 偶数页的页脚
+
+	DifferentFirst   bool // 首页不同，首页的模板为空时首页没有页眉页脚；设置了首页的模板时自动启用
+	DifferentOddEven bool // 奇偶页不同；设置了偶数页的模板时自动启用
+}
+
+// firstPage 判断是否首页不同
+func (h HeaderFooter) firstPage() bool {
+	return h.DifferentFirst || h.FirstHeader != "" || h.FirstFooter != ""
+}
+
+// oddEven 判断是否奇偶页不同
+func (h HeaderFooter) oddEven() bool {
+	return h.DifferentOddEven || h.EvenHeader != "" || h.EvenFooter != ""
+}
+
+// figureLabel 返回图题注的前缀
+func (o Options) figureLabel() string {
+	if o.FigureLabel == "" {
+		return "Figure"
+	}
+	return o.FigureLabel
+}
@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"path"
+	"strings"
+)
+
+// 关系类型
+const (
+	relTypeOfficeDocument = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	relTypeStyles         = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	relTypeImage          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	relTypeHyperlink      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	relTypeFootnotes      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes"
+	relTypeEndnotes       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/endnotes"
+	relTypeNumbering      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	relTypeSettings       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	relTypeCoreProperties = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	relTypeAppProperties  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+
+	relTypeCustomProperties = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+)
+
+// 部件内容类型
+const (
+	contentTypeDocument  = "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"
+	contentTypeStyles    = "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"
+	contentTypeFootnotes = "application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml"
+	contentTypeEndnotes  = "application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml"
+	contentTypeNumbering = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+	contentTypeSettings  = "application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"
+	contentTypeCore      = "application/vnd.openxmlformats-package.core-properties+xml"
+	contentTypeApp       = "application/vnd.openxmlformats-officedocument.extended-properties+xml"
+	contentTypeCustom    = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+)
+
+// defaultContentTypes 按扩展名确定的默认内容类型
+var defaultContentTypes = map[string]string{
+	"xml":  "application/xml",
+	"rels": "application/vnd.openxmlformats-package.relationships+xml",
+	"png":  "image/png",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+}
+
+// part 表示DOCX包中的一个部件
+type part struct {
+	Name        string // 包内路径，如 word/document.xml
+	ContentType string // 内容类型，为空时使用扩展名对应的默认类型
+	Data        string // 部件内容
+}
+
+// relationship 表示部件之间的关系
+type relationship struct {
+	ID       string
+	Type     string
+	Target   string
+	External bool // 是否指向包外资源
+}
+
+// contentTypesXML 根据包中的部件生成[Content_Types].xml
+func contentTypesXML(parts []part) string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+    <Default Extension="xml" ContentType="application/xml"/>
+    <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`
+	seen := map[string]bool{"xml": true, "rels": true}
+	for _, p := range parts {
+		ext := strings.TrimPrefix(path.Ext(p.Name), ".")
+		if p.ContentType == "" && !seen[ext] {
+			seen[ext] = true
+			xml += "\n    " + `<Default Extension="` + ext + `" ContentType="` + defaultContentTypes[ext] + `"/>`
+		}
+	}
+	for _, p := range parts {
+		if p.ContentType != "" {
+			xml += "\n    " + `<Override PartName="/` + p.Name + `" ContentType="` + p.ContentType + `"/>`
+		}
+	}
+	return xml + "\n</Types>"
+}
+
+// relationshipsXML 生成关系部件的内容
+func relationshipsXML(rels []relationship) string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	for _, rel := range rels {
+		xml += "\n    " + `<Relationship Id="` + rel.ID + `" Type="` + rel.Type + `" Target="` + escapeXML(rel.Target) + `"`
+		if rel.External {
+			xml += ` TargetMode="External"`
+		}
+		xml += `/>`
+	}
+	return xml + "\n</Relationships>"
+}
@@ -0,0 +1,93 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// preset 内置的版式预设，设置页面、文档网格、字体字号和标题编号
+type preset struct {
+	section   sectionProps     // 页面设置
+	numbering []numberingLevel // 标题各级的编号格式
+	styles    func(*styleSheet)
+}
+
+// presets 内置的版式预设，键为 Options.Preset 的取值
+var presets = map[string]preset{
+	"gongwen": gongwenPreset(),
+}
+
+// 公文版式的参数：正文三号字（16磅），每页28行、每行28字
+const (
+	gongwenFontSize     = 32 // 三号，单位为半磅
+	gongwenTitleSize    = 44 // 二号
+	gongwenLinesPerPage = 28
+	gongwenCharsPerLine = 28
+)
+
+// gongwenPreset 返回GB/T 9704党政机关公文格式的预设：A4纸，天头37毫米、订口28毫米，版心156×225毫米；
+// 正文仿宋_GB2312三号，标题方正小标宋简体二号；各级标题依次为黑体、楷体、仿宋加粗和仿宋，编号为 一、（一）1.（1）
+func gongwenPreset() preset {
+	section := sectionProps{
+		width: mm(210), height: mm(297),
+		top: mm(37), bottom: mm(35), left: mm(28), right: mm(26),
+		// 页码位于版心下边缘之下约7毫米
+		header: mm(15), footer: mm(22),
+	}
+	section.linePitch = section.textHeight() / gongwenLinesPerPage
+	// 字符间距按每行字数调整，以默认字号为基准
+	charPitch := float64(section.textWidth()) / 20 / gongwenCharsPerLine
+	section.charSpace = int(math.Round((charPitch - gongwenFontSize/2) * 4096))
+
+	return preset{
+		section: section,
+		numbering: []numberingLevel{
+			{"chineseCounting", "%1、", "nothing"},
+			{"chineseCounting", "（%2）", "nothing"},
+			{"decimal", "%3.", "nothing"},
+			{"decimal", "（%4）", "nothing"},
+			{"decimalEnclosedCircle", "%5", "nothing"},
+			{"lowerLetter", "%6.", "nothing"},
+			{"lowerLetter", "（%7）", "nothing"},
+			{"lowerRoman", "%8.", "nothing"},
+			{"lowerRoman", "（%9）", "nothing"},
+		},
+		styles: gongwenStyles,
+	}
+}
+
+// gongwenStyles 按公文格式调整样式：段落首行缩进两字、两端对齐并对齐网格，标题与正文同为三号字
+func gongwenStyles(sheet *styleSheet) {
+	sheet.defaultRPr.fonts = fonts{ascii: "Times New Roman", hAnsi: "Times New Roman", eastAsia: "仿宋_GB2312", cs: "Times New Roman"}
+	sheet.defaultRPr.size = gongwenFontSize
+	sheet.defaultPPr.spacing = `w:after="0" w:line="240" w:lineRule="auto"`
+
+	indent := fmt.Sprintf(`w:firstLineChars="200" w:firstLine="%d"`, gongwenFontSize*20)
+	normal := sheet.style("Normal")
+	normal.pPr.ind, normal.pPr.jc = indent, "both"
+	// 其他段落样式不缩进首行
+	for i := range sheet.styles {
+		st := &sheet.styles[i]
+		if st.kind != "paragraph" || st.id == "Normal" || strings.HasPrefix(st.id, "Heading") || strings.Contains(st.pPr.ind, "firstLine") {
+			continue
+		}
+		st.pPr.ind = strings.TrimSpace(st.pPr.ind + ` w:firstLineChars="0" w:firstLine="0"`)
+	}
+
+	headingFonts := []string{"黑体", "楷体_GB2312", "仿宋_GB2312", "仿宋_GB2312"}
+	for level := 1; level <= 9; level++ {
+		st := sheet.style(fmt.Sprintf("Heading%d", level))
+		font := headingFonts[len(headingFonts)-1]
+		if level <= len(headingFonts) {
+			font = headingFonts[level-1]
+		}
+		st.pPr.spacing = `w:before="0" w:after="0"`
+		st.rPr = runProps{fonts: fonts{eastAsia: font}, bold: level == 3, size: gongwenFontSize}
+	}
+
+	title := sheet.style("Title")
+	title.pPr.spacing = `w:before="0" w:after="0" w:afterLines="100"`
+	title.rPr = runProps{fonts: fonts{eastAsia: "方正小标宋简体"}, size: gongwenTitleSize}
+	sheet.style("Subtitle").rPr.size = gongwenFontSize
+}
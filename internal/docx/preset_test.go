@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestGongwenPreset(t *testing.T) {
+	heading := func(level int, text string) models.Header {
+		return models.Header{Level: level, Inlines: []models.Inline{models.Text{Content: text}}}
+	}
+	doc := models.Document{
+		Metadata: models.Metadata{Title: "关于开展专项检查的通知"},
+		Blocks: []models.Block{
+			models.TableOfContents{},
+			heading(1, "总体要求"),
+			heading(2, "指导思想"),
+			heading(3, "基本原则"),
+			heading(4, "具体措施"),
+			heading(2, "工作目标"),
+			heading(1, "主要任务"),
+		},
+	}
+	g := newGenerator(Options{Preset: "gongwen", TitleBlock: true})
+	xml := g.documentXML(doc)
+
+	t.Run("标题编号", func(t *testing.T) {
+		var numbers []string
+		for _, entry := range g.headings {
+			numbers = append(numbers, entry.number)
+		}
+		if got := strings.Join(numbers, ","); got != "一、,（一）,1.,（1）,（二）,二、" {
+			t.Errorf("标题编号错误: %s", got)
+		}
+		if !strings.Contains(xml, `<w:t xml:space="preserve">一、总体要求</w:t>`) {
+			t.Error("目录中的编号与标题之间不应有空格")
+		}
+		numbering := g.numberingXML()
+		if !strings.Contains(numbering, `<w:numFmt w:val="chineseCounting"/><w:pStyle w:val="Heading2"/><w:suff w:val="nothing"/><w:lvlText w:val="（%2）"/>`) {
+			t.Errorf("多级编号格式错误:\n%s", numbering)
+		}
+	})
+
+	t.Run("页面和网格", func(t *testing.T) {
+		want := `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="2098" w:right="1474" w:bottom="1984" w:left="1587" w:header="850" w:footer="1247" w:gutter="0"/>` +
+			`<w:docGrid w:type="linesAndChars" w:linePitch="455" w:charSpace="-841"/></w:sectPr></w:body>`
+		if !strings.Contains(xml, want) {
+			t.Errorf("节属性错误:\n%s", xml[strings.Index(xml, "<w:sectPr"):])
+		}
+	})
+
+	t.Run("字体字号", func(t *testing.T) {
+		styles := g.stylesXML()
+		for _, want := range []string{
+			`<w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:eastAsia="仿宋_GB2312" w:cs="Times New Roman"/><w:sz w:val="32"/>`,
+			`<w:ind w:firstLineChars="200" w:firstLine="640"/><w:jc w:val="both"/>`,
+			`<w:rPr><w:rFonts w:eastAsia="黑体"/><w:sz w:val="32"/><w:szCs w:val="32"/></w:rPr>`,
+			`<w:rPr><w:rFonts w:eastAsia="楷体_GB2312"/><w:sz w:val="32"/>`,
+			`<w:rPr><w:rFonts w:eastAsia="方正小标宋简体"/><w:sz w:val="44"/>`,
+			`<w:tab w:val="right" w:leader="dot" w:pos="8845"/>`,
+		} {
+			if !strings.Contains(styles, want) {
+				t.Errorf("样式表缺少 %s", want)
+			}
+		}
+	})
+
+	t.Run("未知的预设", func(t *testing.T) {
+		if err := CreateDOCXWithOptions(doc, filepath.Join(t.TempDir(), "x.docx"), Options{Preset: "nosuch"}); err == nil {
+			t.Error("未知的预设应返回错误")
+		}
+	})
+}
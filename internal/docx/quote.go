@@ -0,0 +1,30 @@
+package docx
+
+import (
+	"fmt"
+
+	"goffice/internal/models"
+)
+
+// quoteIndent 每一级引用块的左缩进（单位：twip），与Quote样式的缩进一致
+const quoteIndent = 567
+
+// blockQuoteXML 生成引用块，段落使用Quote样式，嵌套的引用逐级增加缩进
+func (g *generator) blockQuoteXML(q models.BlockQuote, depth int) string {
+	props := `<w:pStyle w:val="Quote"/>`
+	if depth > 1 {
+		props += fmt.Sprintf(`<w:ind w:left="%d"/>`, depth*quoteIndent)
+	}
+	xml := ""
+	for _, block := range q.Blocks {
+		switch b := block.(type) {
+		case models.Paragraph:
+			xml += g.paragraphXML(b, props)
+		case models.BlockQuote:
+			xml += g.blockQuoteXML(b, depth+1)
+		default:
+			xml += g.blockXML(block)
+		}
+	}
+	return xml
+}
@@ -0,0 +1,38 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestBlockQuoteXML(t *testing.T) {
+	quote := models.BlockQuote{Blocks: []models.Block{
+		models.Paragraph{Inlines: []models.Inline{models.Text{Content: "外层"}}},
+		models.BlockQuote{Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{models.Text{Content: "内层"}}},
+			models.BlockQuote{Blocks: []models.Block{
+				models.Paragraph{Inlines: []models.Inline{models.Text{Content: "最内层"}}},
+			}},
+		}},
+		models.Math{LaTeX: "x", Display: true},
+	}}
+	xml := newGenerator(Options{}).blockXML(quote)
+
+	if strings.Count(xml, `<w:pStyle w:val="Quote"/>`) != 3 {
+		t.Errorf("引用块中的每个段落都应使用Quote样式，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:p><w:pPr><w:pStyle w:val="Quote"/><w:rPr></w:rPr></w:pPr><w:r><w:t>外层</w:t></w:r></w:p>`) {
+		t.Error("第一级引用应直接使用Quote样式的缩进")
+	}
+	if !strings.Contains(xml, `<w:ind w:left="1134"/><w:rPr></w:rPr></w:pPr><w:r><w:t>内层</w:t>`) {
+		t.Error("第二级引用应增加缩进")
+	}
+	if !strings.Contains(xml, `<w:ind w:left="1701"/><w:rPr></w:rPr></w:pPr><w:r><w:t>最内层</w:t>`) {
+		t.Error("第三级引用应继续增加缩进")
+	}
+	if !strings.Contains(xml, `<m:oMathPara>`) {
+		t.Error("引用块中的其他块元素应正常生成")
+	}
+}
@@ -0,0 +1,37 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// rawXML 返回原样插入document.xml的OpenXML片段。其他格式的内容被忽略；
+// 片段不是格式良好的XML时记录错误并丢弃，以免生成损坏的文档
+func (g *generator) rawXML(format, content string) string {
+	if format != "openxml" {
+		g.warn("忽略不支持的原始内容格式: %s", format)
+		return ""
+	}
+	if err := checkWellFormed(content); err != nil {
+		err = fmt.Errorf("原始OpenXML内容不是格式良好的XML: %v\n%s", err, content)
+		if g.err == nil {
+			g.err = err
+		}
+		return ""
+	}
+	return content
+}
+
+// checkWellFormed 检查XML片段是否格式良好：标签正确嵌套和闭合，实体和属性合法
+func checkWellFormed(fragment string) error {
+	d := xml.NewDecoder(strings.NewReader("<raw>" + fragment + "</raw>"))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package docx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestRawXML(t *testing.T) {
+	field := `<w:p><w:fldSimple w:instr=" DATE "><w:r><w:t>日期</w:t></w:r></w:fldSimple></w:p>`
+	doc := models.Document{Blocks: []models.Block{
+		models.RawBlock{Format: "openxml", Content: field},
+		models.RawBlock{Format: "html", Content: "<b>忽略</b>"},
+		models.Paragraph{Inlines: []models.Inline{models.RawInline{Format: "openxml", Content: `<w:r><w:tab/></w:r>`}}},
+	}}
+	var warnings []string
+	xml := newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }}).documentXML(doc)
+	if !strings.Contains(xml, field) || !strings.Contains(xml, `<w:r><w:tab/></w:r>`) {
+		t.Errorf("OpenXML内容应原样输出，实际XML为:\n%s", xml)
+	}
+	if strings.Contains(xml, "忽略") {
+		t.Error("其他格式的原始内容应被忽略")
+	}
+	if len(warnings) != 1 || warnings[0] != "忽略不支持的原始内容格式: html" {
+		t.Errorf("忽略其他格式的原始内容时应给出警告，实际为%q", warnings)
+	}
+
+	t.Run("格式错误的内容", func(t *testing.T) {
+		bad := models.Document{Blocks: []models.Block{models.RawBlock{Format: "openxml", Content: "<w:p><w:r></w:p>"}}}
+		g := newGenerator(Options{})
+		if xml := g.documentXML(bad); strings.Contains(xml, "<w:r></w:p>") {
+			t.Error("格式错误的内容不应写入文档")
+		}
+		if g.err == nil {
+			t.Error("格式错误的内容应记录错误")
+		}
+
+		filename := filepath.Join(t.TempDir(), "bad.docx")
+		err := CreateDOCX(bad, filename)
+		if err == nil || !strings.Contains(err.Error(), "不是格式良好的XML") {
+			t.Errorf("应返回明确的错误，实际为%v", err)
+		}
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Error("出错时不应创建文件")
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// 参考文档中可能复用的其他关系类型
+const (
+	relTypeTheme     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	relTypeFontTable = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable"
+	relTypeHeader    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	relTypeFooter    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+)
+
+// referenceDoc 读入内存的参考文档
+type referenceDoc struct {
+	files     map[string]string // 包内所有文件的内容，键为不带前导 / 的路径
+	defaults  map[string]string // 扩展名到默认内容类型的映射
+	overrides map[string]string // 部件名到内容类型的映射
+	warnings  []string          // 读取部件时遇到的问题，复用参考文档时作为警告报告
+}
+
+// referenceParts 从参考文档复用的部件。样式、编号和设置在打包时与生成的内容合并
+type referenceParts struct {
+	styles    string // 参考文档的styles.xml
+	numbering string // 参考文档的numbering.xml，没有时为空
+	settings  string // 参考文档的settings.xml，没有时为空
+	parts     []part // 原样复制的部件，如主题、字体表、页眉页脚及其引用的图片
+}
+
+// loadReference 读取参考DOCX文件
+func loadReference(filename string) (*referenceDoc, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开参考文档: %v", err)
+	}
+	defer zr.Close()
+	ref := &referenceDoc{files: map[string]string{}, defaults: map[string]string{}, overrides: map[string]string{}}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("无法读取参考文档中的 %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("无法读取参考文档中的 %s: %v", f.Name, err)
+		}
+		ref.files[strings.TrimPrefix(f.Name, "/")] = string(data)
+	}
+	if _, ok := ref.files["word/document.xml"]; !ok {
+		return nil, fmt.Errorf("参考文档中缺少word/document.xml")
+	}
+
+	var types struct {
+		Defaults []struct {
+			Extension   string `xml:"Extension,attr"`
+			ContentType string `xml:"ContentType,attr"`
+		} `xml:"Default"`
+		Overrides []struct {
+			PartName    string `xml:"PartName,attr"`
+			ContentType string `xml:"ContentType,attr"`
+		} `xml:"Override"`
+	}
+	if err := xml.Unmarshal([]byte(ref.files["[Content_Types].xml"]), &types); err != nil {
+		return nil, fmt.Errorf("参考文档的[Content_Types].xml无效: %v", err)
+	}
+	for _, d := range types.Defaults {
+		ref.defaults[strings.ToLower(d.Extension)] = d.ContentType
+	}
+	for _, o := range types.Overrides {
+		ref.overrides[strings.TrimPrefix(o.PartName, "/")] = o.ContentType
+	}
+	return ref, nil
+}
+
+// contentType 返回参考文档中部件的内容类型
+func (r *referenceDoc) contentType(name string) string {
+	if t, ok := r.overrides[name]; ok {
+		return t
+	}
+	return r.defaults[strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))]
+}
+
+// relationships 返回参考文档中部件的关系，部件没有关系时返回nil
+func (r *referenceDoc) relationships(name string) []relationship {
+	data, ok := r.files[relsName(name)]
+	if !ok {
+		return nil
+	}
+	var parsed struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Type       string `xml:"Type,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal([]byte(data), &parsed); err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("忽略参考文档中无效的关系部件 %s: %v", relsName(name), err))
+		return nil
+	}
+	var rels []relationship
+	for _, rel := range parsed.Relationships {
+		rels = append(rels, relationship{ID: rel.ID, Type: rel.Type, Target: rel.Target, External: rel.TargetMode == "External"})
+	}
+	return rels
+}
+
+// copyPart 复制部件及其通过关系引用的包内部件
+func (r *referenceDoc) copyPart(name string, copied map[string]bool) []part {
+	if copied[name] {
+		return nil
+	}
+	copied[name] = true
+	return append([]part{{Name: name, ContentType: r.contentType(name), Data: r.files[name]}}, r.copyDependencies(name, name, copied)...)
+}
+
+// copyDependencies 复制部件引用的包内部件并写出改写后的关系部件，newName为部件在生成的文档中的路径。
+// word/media中的文件加上 reference- 前缀，以免与生成的图片重名
+func (r *referenceDoc) copyDependencies(name, newName string, copied map[string]bool) []part {
+	rels := r.relationships(name)
+	if len(rels) == 0 {
+		return nil
+	}
+	var parts []part
+	for i, rel := range rels {
+		if rel.External {
+			continue
+		}
+		target := resolveTarget(name, rel.Target)
+		if _, ok := r.files[target]; !ok {
+			continue
+		}
+		if strings.HasPrefix(target, "word/media/") {
+			rels[i].Target = path.Join(path.Dir(rel.Target), "reference-"+path.Base(rel.Target))
+			if !copied[target] {
+				copied[target] = true
+				parts = append(parts, part{Name: "word/media/reference-" + path.Base(target), ContentType: r.contentType(target), Data: r.files[target]})
+			}
+			continue
+		}
+		parts = append(parts, r.copyPart(target, copied)...)
+	}
+	return append(parts, part{Name: relsName(newName), Data: relationshipsXML(rels)})
+}
+
+// sectPrPattern 匹配正文末尾的节属性
+var sectPrPattern = regexp.MustCompile(`(?s)<w:sectPr\b(?:[^>]*/>|.*</w:sectPr>)\s*</w:body>`)
+
+// sectPr 返回参考文档正文末尾的节属性，没有时返回空字符串
+func (r *referenceDoc) sectPr() string {
+	m := sectPrPattern.FindString(r.files["word/document.xml"])
+	if m == "" {
+		return ""
+	}
+	// 正文中可能有多个段落级的节属性，只保留最后一个
+	m = m[strings.LastIndex(m, "<w:sectPr"):]
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m), "</w:body>"))
+}
+
+// relIDPattern 匹配带关系ID的空元素，如页眉引用
+var relIDPattern = regexp.MustCompile(`<w:\w+\b[^>]*\br:id="([^"]*)"[^>]*/>`)
+
+// useReference 复用参考文档的样式、主题、编号、设置、字体表、页眉页脚和节属性。
+// 正文中的其他关系（如图片）随正文一起被替换
+func (g *generator) useReference(ref *referenceDoc) {
+	g.reference = &referenceParts{}
+	copied := map[string]bool{}
+	ids := map[string]string{}
+	for _, rel := range ref.relationships("word/document.xml") {
+		if rel.External {
+			continue
+		}
+		name := resolveTarget("word/document.xml", rel.Target)
+		data, ok := ref.files[name]
+		if !ok {
+			continue
+		}
+		switch rel.Type {
+		case relTypeStyles:
+			g.reference.styles = data
+			g.reference.parts = append(g.reference.parts, ref.copyDependencies(name, "word/styles.xml", copied)...)
+		case relTypeNumbering:
+			g.reference.numbering = data
+			g.reference.parts = append(g.reference.parts, ref.copyDependencies(name, "word/numbering.xml", copied)...)
+			// 标题编号使用参考文档中未占用的编号
+			g.headingNumID = maxAttr(data, `w:numId="`) + 1
+		case relTypeSettings:
+			g.reference.settings = data
+			g.reference.parts = append(g.reference.parts, ref.copyDependencies(name, "word/settings.xml", copied)...)
+		case relTypeTheme, relTypeFontTable, relTypeHeader, relTypeFooter:
+			g.reference.parts = append(g.reference.parts, ref.copyPart(name, copied)...)
+			ids[rel.ID] = g.addRelationship(rel.Type, rel.Target, false)
+		}
+	}
+	for _, message := range ref.warnings {
+		g.warn("%s", message)
+	}
+	// 节属性中的页眉页脚引用改用新的关系ID，其他未复用的关系（如打印机设置）被去掉
+	sectPr := ref.sectPr()
+	if sectPr == "" {
+		return
+	}
+	g.sectPr = relIDPattern.ReplaceAllStringFunc(sectPr, func(element string) string {
+		id := relIDPattern.FindStringSubmatch(element)[1]
+		if newID, ok := ids[id]; ok {
+			return strings.Replace(element, `r:id="`+id+`"`, `r:id="`+newID+`"`, 1)
+		}
+		return ""
+	})
+	// 选项中的页面设置优先于参考文档
+	g.section = sectionFromXML(g.sectPr, g.section)
+	if g.opts.Page != (PageSetup{}) {
+		if err := g.section.apply(g.opts.Page); err != nil && g.err == nil {
+			g.err = err
+		}
+		g.sectPr = setSectPrElement(setSectPrElement(g.sectPr, g.section.pgSzXML()), g.section.pgMarXML())
+	}
+}
+
+// relsName 返回部件对应的关系部件路径，如 word/_rels/document.xml.rels
+func relsName(name string) string {
+	return path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+}
+
+// resolveTarget 将关系的目标解析为包内路径，相对路径相对于源部件所在目录
+func resolveTarget(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(source), target)
+}
@@ -0,0 +1,182 @@
+package docx
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+// writeZip 将文件写入ZIP包，用于构造参考文档
+func writeZip(t *testing.T, filename string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, data := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readDOCX 读出DOCX包中的所有文件
+func readDOCX(t *testing.T, filename string) map[string]string {
+	t.Helper()
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func TestReferenceDoc(t *testing.T) {
+	dir := t.TempDir()
+	reference := filepath.Join(dir, "reference.docx")
+	writeZip(t, reference, map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Default Extension="png" ContentType="image/png"/>
+<Override PartName="/word/document.xml" ContentType="` + contentTypeDocument + `"/>
+<Override PartName="/word/styles.xml" ContentType="` + contentTypeStyles + `"/>
+<Override PartName="/word/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>
+<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>
+</Types>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+			`<w:p><w:r><w:t>模板正文</w:t></w:r></w:p>` +
+			`<w:sectPr><w:headerReference w:type="default" r:id="rId7"/><w:pgSz w:w="11906" w:h="16838"/><w:printerSettings r:id="rId9"/></w:sectPr></w:body></w:document>`,
+		"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="` + relTypeStyles + `" Target="styles.xml"/>` +
+			`<Relationship Id="rId4" Type="` + relTypeNumbering + `" Target="numbering.xml"/>` +
+			`<Relationship Id="rId5" Type="` + relTypeSettings + `" Target="settings.xml"/>` +
+			`<Relationship Id="rId6" Type="` + relTypeTheme + `" Target="theme/theme1.xml"/>` +
+			`<Relationship Id="rId7" Type="` + relTypeHeader + `" Target="header1.xml"/>` +
+			`<Relationship Id="rId8" Type="` + relTypeImage + `" Target="media/body.png"/>` +
+			`<Relationship Id="rId9" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/printerSettings" Target="printerSettings/printerSettings1.bin"/>` +
+			`</Relationships>`,
+		"word/styles.xml": `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:rPr><w:color w:val="C00000"/></w:rPr></w:style></w:styles>`,
+		"word/numbering.xml": `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:abstractNum w:abstractNumId="0"/><w:abstractNum w:abstractNumId="3"/><w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+			`<w:num w:numId="4"><w:abstractNumId w:val="3"/></w:num></w:numbering>`,
+		"word/settings.xml": `<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:zoom w:percent="100"/><w:footnotePr><w:footnote w:id="-1"/><w:footnote w:id="0"/></w:footnotePr><w:compat/><w:rsids/></w:settings>`,
+		"word/theme/theme1.xml":       `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="品牌主题"/>`,
+		"word/header1.xml":            `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>公司页眉</w:t></w:r></w:p></w:hdr>`,
+		"word/_rels/header1.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/></Relationships>`,
+		"word/media/image1.png":       "logo",
+		"word/media/body.png":         "body",
+	})
+
+	doc := models.Document{Blocks: []models.Block{
+		models.Header{Level: 1, Inlines: []models.Inline{models.Text{Content: "第一章"}}},
+		models.Paragraph{Inlines: []models.Inline{models.Code{Content: "code"}}},
+	}}
+	out := filepath.Join(dir, "out.docx")
+	if err := CreateDOCXWithOptions(doc, out, Options{ReferenceDoc: reference, NumberHeadings: true, TOC: true}); err != nil {
+		t.Fatal(err)
+	}
+	files := readDOCX(t, out)
+	rels := files["word/_rels/document.xml.rels"]
+
+	t.Run("样式", func(t *testing.T) {
+		styles := files["word/styles.xml"]
+		if !strings.Contains(styles, `<w:color w:val="C00000"/>`) {
+			t.Error("应保留参考文档中的样式")
+		}
+		if strings.Count(styles, `w:styleId="Heading1"`) != 1 || !strings.Contains(styles, `w:styleId="VerbatimChar"`) {
+			t.Errorf("应只补充参考文档中没有的样式:\n%s", styles)
+		}
+	})
+
+	t.Run("主题和页眉", func(t *testing.T) {
+		if !strings.Contains(files["word/theme/theme1.xml"], "品牌主题") || !strings.Contains(files["word/header1.xml"], "公司页眉") {
+			t.Fatal("缺少参考文档中的主题或页眉")
+		}
+		if files["word/media/reference-image1.png"] != "logo" ||
+			!strings.Contains(files["word/_rels/header1.xml.rels"], `Target="media/reference-image1.png"`) {
+			t.Error("页眉中的图片应被复制并改名")
+		}
+		if _, ok := files["word/media/body.png"]; ok {
+			t.Error("参考文档正文中的图片不应被复制")
+		}
+		if !strings.Contains(files["[Content_Types].xml"], `<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>`) {
+			t.Error("内容类型中缺少页眉部件")
+		}
+	})
+
+	t.Run("节属性", func(t *testing.T) {
+		document := files["word/document.xml"]
+		if strings.Contains(document, "模板正文") {
+			t.Error("参考文档的正文应被替换")
+		}
+		m := regexp.MustCompile(`<w:sectPr><w:headerReference w:type="default" r:id="(rId\d+)"/><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:body>`).FindStringSubmatch(document)
+		if m == nil {
+			t.Fatalf("节属性未正确复用:\n%s", document)
+		}
+		if !strings.Contains(rels, `Id="`+m[1]+`" Type="`+relTypeHeader+`" Target="header1.xml"`) {
+			t.Errorf("页眉引用的关系ID不正确: %s\n%s", m[1], rels)
+		}
+	})
+
+	t.Run("编号和设置", func(t *testing.T) {
+		numbering := files["word/numbering.xml"]
+		if !strings.Contains(numbering, `<w:abstractNum w:abstractNumId="4">`) ||
+			!strings.Contains(numbering, `<w:num w:numId="5"><w:abstractNumId w:val="4"/></w:num></w:numbering>`) ||
+			strings.Index(numbering, `w:abstractNumId="4"`) > strings.Index(numbering, `<w:num `) {
+			t.Errorf("标题编号未正确合并:\n%s", numbering)
+		}
+		if !strings.Contains(files["word/document.xml"], `<w:numId w:val="5"/>`) {
+			t.Error("标题应引用合并后的编号")
+		}
+		want := `<w:zoom w:percent="100"/><w:updateFields w:val="true"/><w:compat/><w:rsids/>`
+		if settings := files["word/settings.xml"]; !strings.Contains(settings, want) {
+			t.Errorf("设置合并错误，应去掉不需要的脚注设置并按顺序插入:\n%s", settings)
+		}
+	})
+
+	t.Run("忽略的样式选项", func(t *testing.T) {
+		var warnings []string
+		opts := Options{ReferenceDoc: reference, Preset: "gongwen", Fonts: Fonts{EastAsian: "楷体"}, Lang: "en-US",
+			Warn: func(message string) { warnings = append(warnings, message) }}
+		if err := CreateDOCXWithOptions(doc, filepath.Join(dir, "ignored.docx"), opts); err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 1 || warnings[0] != "使用参考文档中的样式，忽略版式预设 gongwen 的样式、字体、文档语言" {
+			t.Errorf("参考文档覆盖的选项应给出警告，实际为%q", warnings)
+		}
+	})
+
+	t.Run("无效的参考文档", func(t *testing.T) {
+		err := CreateDOCXWithOptions(doc, filepath.Join(dir, "bad.docx"), Options{ReferenceDoc: filepath.Join(dir, "missing.docx")})
+		if err == nil {
+			t.Error("参考文档不存在时应返回错误")
+		}
+		if _, statErr := os.Stat(filepath.Join(dir, "bad.docx")); statErr == nil {
+			t.Error("出错时不应创建文件")
+		}
+	})
+}
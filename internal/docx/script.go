@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scriptSegment 一段使用同一种文字的文本
+type scriptSegment struct {
+	text     string
+	eastAsia bool // 是否为中日韩文字
+	shared   bool // 是否含有中西文共用的标点，如引号和破折号
+}
+
+// splitScripts 按文字将文本分段。左引号跟随后面的文字，空白和其他共用的标点跟随前面的文字，位于开头时跟随后面的文字
+func splitScripts(text string) []scriptSegment {
+	var segments []scriptSegment
+	var b strings.Builder
+	eastAsia, shared, decided := false, false, false
+	flush := func() {
+		if b.Len() > 0 {
+			segments = append(segments, scriptSegment{text: b.String(), eastAsia: eastAsia, shared: shared})
+			b.Reset()
+		}
+		shared = false
+	}
+	for _, r := range text {
+		switch {
+		case r == '“' || r == '‘':
+			if decided {
+				flush()
+				decided = false
+			}
+			shared = true
+		case isSharedPunct(r) || unicode.IsSpace(r):
+			shared = shared || isSharedPunct(r)
+		case decided && isEastAsian(r) != eastAsia:
+			flush()
+			eastAsia = !eastAsia
+		case !decided:
+			eastAsia, decided = isEastAsian(r), true
+			// 左引号之后的文字与前一段相同时合并为一段
+			if n := len(segments); n > 0 && segments[n-1].eastAsia == eastAsia {
+				last := segments[n-1]
+				segments = segments[:n-1]
+				pending := b.String()
+				b.Reset()
+				b.WriteString(last.text + pending)
+				shared = shared || last.shared
+			}
+		}
+		b.WriteRune(r)
+	}
+	flush()
+	return segments
+}
+
+// isEastAsian 判断字符是否为中日韩文字或全角标点，Word对这些字符使用东亚字体
+func isEastAsian(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo) ||
+		r >= 0x3000 && r <= 0x303F || r >= 0xFF00 && r <= 0xFFEF
+}
+
+// isSharedPunct 判断字符是否为中西文共用的标点。Word无法确定这些字符使用哪种字体，需要用 w:hint 指明
+func isSharedPunct(r rune) bool {
+	switch r {
+	case '“', '”', '‘', '’', '—', '–', '…', '·':
+		return true
+	}
+	return false
+}
+
+// eastAsiaHint 在文本格式中加入 w:hint="eastAsia"，使共用的标点使用东亚字体
+func eastAsiaHint(rPr runProps) runProps {
+	rPr.fonts.hint = "eastAsia"
+	return rPr
+}
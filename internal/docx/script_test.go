@@ -0,0 +1,92 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestSplitScripts(t *testing.T) {
+	t.Run("按文字分段", func(t *testing.T) {
+		segments := splitScripts("使用 Go 语言，version 1.20")
+		var texts []string
+		for _, seg := range segments {
+			texts = append(texts, seg.text)
+		}
+		if got := strings.Join(texts, "|"); got != "使用 |Go |语言，|version 1.20" {
+			t.Errorf("分段错误: %s", got)
+		}
+	})
+
+	t.Run("共用标点跟随上下文", func(t *testing.T) {
+		segments := splitScripts("“引号”和“词”与 “quote”")
+		if len(segments) != 2 || !segments[0].eastAsia || !segments[0].shared || segments[0].text != "“引号”和“词”与 " ||
+			segments[1].eastAsia || segments[1].text != "“quote”" {
+			t.Errorf("共用标点分段错误: %+v", segments)
+		}
+	})
+}
+
+func TestTextRunXML(t *testing.T) {
+	t.Run("不含共用标点时不拆分", func(t *testing.T) {
+		if got := textRunXML(runProps{}, "中文 English"); got != `<w:r><w:t>中文 English</w:t></w:r>` {
+			t.Errorf("不应拆分文本段: %s", got)
+		}
+	})
+
+	t.Run("中文中的引号使用东亚字体", func(t *testing.T) {
+		got := textRunXML(runProps{style: "Hyperlink", bold: true}, "他说“你好”, OK")
+		want := `<w:r><w:rPr><w:rStyle w:val="Hyperlink"/><w:rFonts w:hint="eastAsia"/><w:b/><w:bCs/></w:rPr><w:t>他说“你好”</w:t></w:r>` +
+			`<w:r><w:rPr><w:rStyle w:val="Hyperlink"/><w:b/><w:bCs/></w:rPr><w:t>, OK</w:t></w:r>`
+		if got != want {
+			t.Errorf("文本段生成错误:\n%s", got)
+		}
+	})
+
+	t.Run("已有字体设置", func(t *testing.T) {
+		if got := eastAsiaHint(runProps{fonts: fonts{ascii: "Consolas"}}).XML(); got != `<w:rFonts w:hint="eastAsia" w:ascii="Consolas"/>` {
+			t.Errorf("应在已有的rFonts中加入hint: %s", got)
+		}
+	})
+}
+
+func TestFontsAndLanguage(t *testing.T) {
+	t.Run("默认字体和语言", func(t *testing.T) {
+		g := newGenerator(Options{})
+		g.documentXML(models.Document{})
+		styles := g.stylesXML()
+		for _, want := range []string{
+			`<w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:eastAsia="宋体" w:cs="Times New Roman"/>`,
+			`<w:lang w:val="en-US" w:eastAsia="zh-CN" w:bidi="ar-SA"/>`,
+			`w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:uiPriority w:val="9"/><w:qFormat/>` +
+				`<w:pPr><w:keepNext/><w:keepLines/><w:spacing w:before="480" w:after="240"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:rFonts w:eastAsia="黑体"/>`,
+		} {
+			if !strings.Contains(styles, want) {
+				t.Errorf("样式表缺少 %s", want)
+			}
+		}
+	})
+
+	t.Run("选项中的字体", func(t *testing.T) {
+		g := newGenerator(Options{Fonts: Fonts{Latin: "Arial", EastAsian: "仿宋", HeadingEastAsian: "楷体"}})
+		styles := g.stylesXML()
+		if !strings.Contains(styles, `<w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:eastAsia="仿宋" w:cs="Arial"/>`) ||
+			strings.Count(styles, `<w:rFonts w:eastAsia="楷体"/>`) != len(headingStyleIDs) {
+			t.Errorf("字体选项未生效:\n%s", styles)
+		}
+	})
+
+	t.Run("元数据和选项中的语言", func(t *testing.T) {
+		g := newGenerator(Options{})
+		g.documentXML(models.Document{Metadata: models.Metadata{Lang: "ja"}})
+		if !strings.Contains(g.stylesXML(), `<w:lang w:val="en-US" w:eastAsia="ja-JP" w:bidi="ar-SA"/>`) {
+			t.Error("元数据中的语言未生效")
+		}
+		g = newGenerator(Options{Lang: "en_GB"})
+		g.documentXML(models.Document{Metadata: models.Metadata{Lang: "ja"}})
+		if !strings.Contains(g.stylesXML(), `<w:lang w:val="en-GB" w:eastAsia="zh-CN" w:bidi="ar-SA"/>`) {
+			t.Error("选项中的语言应覆盖元数据")
+		}
+	})
+}
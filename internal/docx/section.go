@@ -0,0 +1,346 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// sectionProps 节属性：纸张、页边距和文档网格，长度的单位均为缇（1/20磅）
+type sectionProps struct {
+	width, height            int  // 纸张宽度和高度
+	landscape                bool // 是否为横向
+	top, right, bottom, left int  // 页边距
+	header, footer           int  // 页眉和页脚距纸张边缘的距离
+	gutter                   int  // 装订线宽度
+	columns, columnGap       int  // 栏数和栏间距，栏数为0表示不分栏
+	linePitch                int  // 文档网格的行距，0表示不使用网格
+	charSpace                int  // 文档网格的字符间距调整，单位为1/4096磅，相对于默认字号
+}
+
+// pageSizes 内置的纸张大小，键为小写的名称
+var pageSizes = map[string][2]int{
+	"a3":     {mm(297), mm(420)},
+	"a4":     {mm(210), mm(297)},
+	"a5":     {mm(148), mm(210)},
+	"b5":     {mm(176), mm(250)},
+	"letter": {12240, 15840},
+	"legal":  {12240, 20160},
+}
+
+// defaultSection 默认的页面设置：A4纵向，页边距1英寸，页眉页脚距边缘0.5英寸
+func defaultSection() sectionProps {
+	return sectionProps{
+		width: pageSizes["a4"][0], height: pageSizes["a4"][1],
+		top: 1440, right: 1440, bottom: 1440, left: 1440,
+		header: 720, footer: 720,
+	}
+}
+
+// textWidth 返回版心的宽度
+func (s sectionProps) textWidth() int {
+	return s.width - s.left - s.right - s.gutter
+}
+
+// columnWidth 返回一栏的宽度，不分栏时为版心宽度
+func (s sectionProps) columnWidth() int {
+	if s.columns < 2 {
+		return s.textWidth()
+	}
+	return (s.textWidth() - s.columnGap*(s.columns-1)) / s.columns
+}
+
+// textHeight 返回版心的高度
+func (s sectionProps) textHeight() int {
+	return s.height - s.top - s.bottom
+}
+
+// setOrientation 设置纸张方向，必要时交换纸张的宽度和高度
+func (s *sectionProps) setOrientation(landscape bool) {
+	if landscape != (s.width > s.height) {
+		s.width, s.height = s.height, s.width
+	}
+	s.landscape = landscape
+}
+
+// apply 按选项修改页面设置，未设置的选项保持不变
+func (s *sectionProps) apply(page PageSetup) error {
+	if page.Size != "" {
+		if size, ok := pageSizes[strings.ToLower(page.Size)]; ok {
+			s.width, s.height = size[0], size[1]
+		} else {
+			size, err := parseLengths(page.Size, "x")
+			if err != nil || len(size) != 2 {
+				return fmt.Errorf("无效的纸张大小 %q，应为 A4、A3、Letter 等或 宽x高，如 184x260mm", page.Size)
+			}
+			s.width, s.height = size[0], size[1]
+		}
+		s.landscape = false
+	}
+	switch strings.ToLower(page.Orientation) {
+	case "":
+	case "portrait":
+		s.setOrientation(false)
+	case "landscape":
+		s.setOrientation(true)
+	default:
+		return fmt.Errorf("无效的纸张方向 %q，应为 portrait 或 landscape", page.Orientation)
+	}
+	if page.Margins != "" {
+		margins, err := parseLengths(page.Margins, ",")
+		if err != nil {
+			return fmt.Errorf("无效的页边距 %q: %v", page.Margins, err)
+		}
+		switch len(margins) {
+		case 1:
+			s.top, s.right, s.bottom, s.left = margins[0], margins[0], margins[0], margins[0]
+		case 2:
+			s.top, s.right, s.bottom, s.left = margins[0], margins[1], margins[0], margins[1]
+		case 4:
+			s.top, s.right, s.bottom, s.left = margins[0], margins[1], margins[2], margins[3]
+		default:
+			return fmt.Errorf("无效的页边距 %q，应为一个、两个或四个长度", page.Margins)
+		}
+	}
+	for _, length := range []struct {
+		name, value string
+		target      *int
+	}{
+		{"装订线宽度", page.Gutter, &s.gutter},
+		{"页眉距离", page.Header, &s.header},
+		{"页脚距离", page.Footer, &s.footer},
+	} {
+		if length.value == "" {
+			continue
+		}
+		v, err := parseLengths(length.value, ",")
+		if err != nil || len(v) != 1 {
+			return fmt.Errorf("无效的%s %q", length.name, length.value)
+		}
+		*length.target = v[0]
+	}
+	if s.textWidth() <= 0 || s.textHeight() <= 0 {
+		return fmt.Errorf("页边距超出了纸张大小")
+	}
+	return nil
+}
+
+// lengthPattern 匹配带可选单位的长度，如 25mm、1.5in
+var lengthPattern = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)\s*(mm|cm|in|pt)?$`)
+
+// parseLengths 解析以sep分隔的一组长度并换算为缇。没有单位的长度使用最后一个长度的单位，都没有单位时按毫米计
+func parseLengths(s, sep string) ([]int, error) {
+	fields := strings.Split(strings.ToLower(s), sep)
+	unit := "mm"
+	if m := lengthPattern.FindStringSubmatch(strings.TrimSpace(fields[len(fields)-1])); m != nil && m[2] != "" {
+		unit = m[2]
+	}
+	var lengths []int
+	for _, field := range fields {
+		m := lengthPattern.FindStringSubmatch(strings.TrimSpace(field))
+		if m == nil {
+			return nil, fmt.Errorf("无法识别的长度 %q", strings.TrimSpace(field))
+		}
+		v, _ := strconv.ParseFloat(m[1], 64)
+		u := m[2]
+		if u == "" {
+			u = unit
+		}
+		switch u {
+		case "mm":
+			lengths = append(lengths, mm(v))
+		case "cm":
+			lengths = append(lengths, mm(v*10))
+		case "in":
+			lengths = append(lengths, int(v*1440+0.5))
+		case "pt":
+			lengths = append(lengths, int(v*20+0.5))
+		}
+	}
+	return lengths, nil
+}
+
+// pgSzXML 生成纸张大小元素
+func (s sectionProps) pgSzXML() string {
+	if s.landscape {
+		return fmt.Sprintf(`<w:pgSz w:w="%d" w:h="%d" w:orient="landscape"/>`, s.width, s.height)
+	}
+	return fmt.Sprintf(`<w:pgSz w:w="%d" w:h="%d"/>`, s.width, s.height)
+}
+
+// pgMarXML 生成页边距元素
+func (s sectionProps) pgMarXML() string {
+	return fmt.Sprintf(`<w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="%d" w:footer="%d" w:gutter="%d"/>`,
+		s.top, s.right, s.bottom, s.left, s.header, s.footer, s.gutter)
+}
+
+// XML 生成 <w:sectPr>，子元素按CT_SectPr中的顺序写入
+func (s sectionProps) XML() string {
+	xml := `<w:sectPr>` + s.pgSzXML() + s.pgMarXML()
+	if s.linePitch > 0 {
+		xml += fmt.Sprintf(`<w:docGrid w:type="linesAndChars" w:linePitch="%d" w:charSpace="%d"/>`, s.linePitch, s.charSpace)
+	}
+	return xml + `</w:sectPr>`
+}
+
+// sectionFromXML 从已有的节属性中读取纸张大小和页边距，缺少的属性使用base中的值
+func sectionFromXML(sectPr string, base sectionProps) sectionProps {
+	s := base
+	for _, attr := range []struct {
+		element, name string
+		target        *int
+	}{
+		{"w:pgSz", "w:w", &s.width}, {"w:pgSz", "w:h", &s.height},
+		{"w:pgMar", "w:top", &s.top}, {"w:pgMar", "w:right", &s.right}, {"w:pgMar", "w:bottom", &s.bottom}, {"w:pgMar", "w:left", &s.left},
+		{"w:pgMar", "w:header", &s.header}, {"w:pgMar", "w:footer", &s.footer}, {"w:pgMar", "w:gutter", &s.gutter},
+	} {
+		i := elementIndex(sectPr, attr.element)
+		if i == -1 {
+			continue
+		}
+		element := sectPr[i : i+strings.IndexByte(sectPr[i:], '>')]
+		m := regexp.MustCompile(`\s` + attr.name + `="(-?\d+)"`).FindStringSubmatch(element)
+		if m == nil {
+			continue
+		}
+		*attr.target, _ = strconv.Atoi(m[1])
+	}
+	s.landscape = s.width > s.height
+	return s
+}
+
+// sectPrOrder CT_SectPr中子元素的顺序
+var sectPrOrder = []string{
+	"w:headerReference", "w:footerReference", "w:footnotePr", "w:endnotePr", "w:type", "w:pgSz", "w:pgMar",
+	"w:paperSrc", "w:pgBorders", "w:lnNumType", "w:pgNumType", "w:cols", "w:formProt", "w:vAlign", "w:noEndnote",
+	"w:titlePg", "w:textDirection", "w:bidi", "w:rtlGutter", "w:docGrid", "w:printerSettings", "w:sectPrChange",
+}
+
+// setSectPrElement 在节属性中写入element，替换同名的已有元素，没有时按CT_SectPr中的顺序插入
+func setSectPrElement(sectPr, element string) string {
+	name := element[1:strings.IndexAny(element, " />")]
+	if strings.HasSuffix(sectPr, "/>") {
+		sectPr = strings.TrimSuffix(sectPr, "/>") + "></w:sectPr>"
+	}
+	// 跳过 <w:sectPr 本身
+	start := strings.IndexByte(sectPr, '>') + 1
+	if i := elementIndex(sectPr[start:], name); i != -1 {
+		i += start
+		end := i + strings.IndexByte(sectPr[i:], '>') + 1
+		if sectPr[end-2] != '/' {
+			end = strings.Index(sectPr[i:], "</"+name+">") + i + len("</"+name+">")
+		}
+		return sectPr[:i] + element + sectPr[end:]
+	}
+	return insertSectPrElement(sectPr, element)
+}
+
+// insertSectPrElement 按CT_SectPr中的顺序插入element，位于已有的同名元素之后。element可以是多个同名元素
+func insertSectPrElement(sectPr, element string) string {
+	name := element[1:strings.IndexAny(element, " />")]
+	if strings.HasSuffix(sectPr, "/>") {
+		sectPr = strings.TrimSuffix(sectPr, "/>") + "></w:sectPr>"
+	}
+	start := strings.IndexByte(sectPr, '>') + 1
+	at := strings.LastIndex(sectPr, "</w:sectPr>")
+	for i, following := range sectPrOrder {
+		if following != name {
+			continue
+		}
+		for _, next := range sectPrOrder[i+1:] {
+			if j := elementIndex(sectPr[start:], next); j != -1 && j+start < at {
+				at = j + start
+			}
+		}
+	}
+	return sectPr[:at] + element + sectPr[at:]
+}
+
+// removeSectPrElements 去掉节属性中所有给定名称的元素，如页眉引用
+func removeSectPrElements(sectPr, name string) string {
+	for {
+		i := elementIndex(sectPr, name)
+		if i == -1 {
+			return sectPr
+		}
+		end := i + strings.IndexByte(sectPr[i:], '>') + 1
+		if sectPr[end-2] != '/' {
+			end = strings.Index(sectPr[i:], "</"+name+">") + i + len("</"+name+">")
+		}
+		sectPr = sectPr[:i] + sectPr[end:]
+	}
+}
+
+// sectionBreakXML 结束当前节并开始新节。当前节的属性写在节末的空段落中，新节从新页开始并可改变纸张方向
+func (g *generator) sectionBreakXML(b models.SectionBreak) string {
+	xml := `<w:p><w:pPr>` + g.sectPr + `</w:pPr></w:p>`
+	g.sectPr = removeSectPrElements(g.sectPr, "w:type")
+	if b.Orientation != "" {
+		g.section.setOrientation(b.Orientation == "landscape")
+		g.sectPr = setSectPrElement(g.sectPr, g.section.pgSzXML())
+	}
+	return xml
+}
+
+// defaultColumnGap 默认的栏间距，约为两个五号字
+const defaultColumnGap = 425
+
+// useColumns 设置当前节的栏数和栏间距
+func (g *generator) useColumns(count, gap int) {
+	g.section.columns, g.section.columnGap = count, gap
+	if count > 1 {
+		g.sectPr = setSectPrElement(g.sectPr, fmt.Sprintf(`<w:cols w:num="%d" w:space="%d"/>`, count, gap))
+	} else {
+		g.sectPr = removeSectPrElements(g.sectPr, "w:cols")
+	}
+}
+
+// setColumns 结束当前节并设置之后的内容的栏数，返回结束当前节的段落。新节与当前节连续排版，不另起一页
+func (g *generator) setColumns(count, gap int) string {
+	xml := `<w:p><w:pPr>` + g.sectPr + `</w:pPr></w:p>`
+	g.sectPr = setSectPrElement(g.sectPr, `<w:type w:val="continuous"/>`)
+	g.useColumns(count, gap)
+	return xml
+}
+
+// documentColumns 返回正文的栏数和栏间距，来自选项或元数据中的 columns 和 column-gap
+func (g *generator) documentColumns(meta models.Metadata) (int, int) {
+	count, gap := g.opts.Columns, g.opts.ColumnGap
+	if count == 0 {
+		count, _ = strconv.Atoi(meta.Extra["columns"])
+	}
+	if gap == "" {
+		gap = meta.Extra["column-gap"]
+	}
+	return count, g.columnGap(gap)
+}
+
+// columnGap 将栏间距换算为缇，为空或无效时使用默认间距，无效时给出警告
+func (g *generator) columnGap(gap string) int {
+	if gap == "" {
+		return defaultColumnGap
+	}
+	if v, err := parseLengths(gap, ","); err == nil && len(v) == 1 {
+		return v[0]
+	}
+	g.warn("忽略无效的栏间距: %s", gap)
+	return defaultColumnGap
+}
+
+// columnsXML 将分栏块放在单独的节中，之后恢复原来的栏数
+func (g *generator) columnsXML(c models.Columns) string {
+	count, gap := g.section.columns, g.section.columnGap
+	xml := g.setColumns(c.Count, g.columnGap(c.Gap))
+	for _, block := range c.Blocks {
+		xml += g.blockXML(block)
+	}
+	return xml + g.setColumns(count, gap)
+}
+
+// mm 将毫米换算为缇
+func mm(v float64) int {
+	return int(v*1440/25.4 + 0.5)
+}
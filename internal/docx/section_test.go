@@ -0,0 +1,220 @@
+package docx
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestPageSetup(t *testing.T) {
+	t.Run("默认页面", func(t *testing.T) {
+		xml := GenerateDocumentXML(models.Document{})
+		want := `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr></w:body>`
+		if !strings.Contains(xml, want) {
+			t.Errorf("缺少默认的节属性:\n%s", xml)
+		}
+	})
+
+	t.Run("纸张和页边距", func(t *testing.T) {
+		g := newGenerator(Options{Page: PageSetup{Size: "Letter", Orientation: "landscape", Margins: "1,0.75in", Gutter: "10mm", Header: "12pt", Footer: "1cm"}})
+		if g.err != nil {
+			t.Fatal(g.err)
+		}
+		want := `<w:sectPr><w:pgSz w:w="15840" w:h="12240" w:orient="landscape"/><w:pgMar w:top="1440" w:right="1080" w:bottom="1440" w:left="1080" w:header="240" w:footer="567" w:gutter="567"/></w:sectPr>`
+		if g.sectPr != want {
+			t.Errorf("节属性错误:\n%s", g.sectPr)
+		}
+		if g.textWidth() != 15840-1080*2-567 {
+			t.Errorf("版心宽度错误: %d", g.textWidth())
+		}
+
+		g = newGenerator(Options{Page: PageSetup{Size: "184x260", Margins: "20,15,20,25mm"}})
+		want = `<w:pgSz w:w="10431" w:h="14740"/><w:pgMar w:top="1134" w:right="850" w:bottom="1134" w:left="1417"`
+		if !strings.Contains(g.sectPr, want) {
+			t.Errorf("自定义纸张错误:\n%s", g.sectPr)
+		}
+	})
+
+	t.Run("预设与选项", func(t *testing.T) {
+		g := newGenerator(Options{Preset: "gongwen", Page: PageSetup{Orientation: "landscape"}})
+		if !strings.Contains(g.sectPr, `<w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/><w:pgMar w:top="2098"`) ||
+			!strings.Contains(g.sectPr, "<w:docGrid") {
+			t.Errorf("应在预设的基础上改变纸张方向:\n%s", g.sectPr)
+		}
+	})
+
+	t.Run("无效的设置", func(t *testing.T) {
+		for _, page := range []PageSetup{
+			{Size: "B9"},
+			{Size: "210x"},
+			{Orientation: "sideways"},
+			{Margins: "1,2,3"},
+			{Margins: "abc"},
+			{Gutter: "-5mm"},
+			{Margins: "120mm"},
+		} {
+			err := CreateDOCXWithOptions(models.Document{}, filepath.Join(t.TempDir(), "x.docx"), Options{Page: page})
+			if err == nil {
+				t.Errorf("%+v 应返回错误", page)
+			}
+		}
+	})
+
+	t.Run("解析长度", func(t *testing.T) {
+		cases := []struct {
+			s, sep string
+			want   []int
+		}{
+			{"25", ",", []int{1417}},
+			{"2.54cm", ",", []int{1440}},
+			{"1, .5in", ",", []int{1440, 720}},
+			{"72pt,1in", ",", []int{1440, 1440}},
+			{"8.5x11in", "x", []int{12240, 15840}},
+			{"210 X 297 MM", "x", []int{11906, 16838}},
+		}
+		for _, c := range cases {
+			got, err := parseLengths(c.s, c.sep)
+			if err != nil || !reflect.DeepEqual(got, c.want) {
+				t.Errorf("parseLengths(%q) = %v, %v，应为 %v", c.s, got, err, c.want)
+			}
+		}
+	})
+
+	t.Run("写入节属性元素", func(t *testing.T) {
+		sectPr := `<w:sectPr w:rsidR="00A1"><w:headerReference w:type="default" r:id="rId7"/><w:pgSz w:w="11906" w:h="16838"/>` +
+			`<w:cols w:num="2"><w:col w:w="4000"/><w:col w:w="4000"/></w:cols><w:docGrid w:linePitch="312"/></w:sectPr>`
+		got := setSectPrElement(sectPr, `<w:pgMar w:top="1"/>`)
+		got = setSectPrElement(got, `<w:cols w:space="425"/>`)
+		got = setSectPrElement(got, `<w:titlePg/>`)
+		got = setSectPrElement(got, `<w:type w:val="continuous"/>`)
+		want := `<w:sectPr w:rsidR="00A1"><w:headerReference w:type="default" r:id="rId7"/><w:type w:val="continuous"/><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1"/>` +
+			`<w:cols w:space="425"/><w:titlePg/><w:docGrid w:linePitch="312"/></w:sectPr>`
+		if got != want {
+			t.Errorf("节属性元素的位置错误:\n%s\n应为:\n%s", got, want)
+		}
+		if got := setSectPrElement(`<w:sectPr/>`, `<w:pgSz w:w="1" w:h="2"/>`); got != `<w:sectPr><w:pgSz w:w="1" w:h="2"/></w:sectPr>` {
+			t.Errorf("空的节属性处理错误: %s", got)
+		}
+	})
+
+	t.Run("分节符", func(t *testing.T) {
+		table := models.Table{
+			Columns: []models.TableColumn{{}},
+			Rows:    []models.TableRow{{Cells: []models.TableCell{{Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "宽表格"}}}}}}}},
+		}
+		doc := models.Document{Blocks: []models.Block{
+			models.Paragraph{Inlines: []models.Inline{models.Text{Content: "纵向"}}},
+			models.SectionBreak{Orientation: "landscape"},
+			table,
+			models.SectionBreak{},
+		}}
+		xml := GenerateDocumentXML(doc)
+		portrait := `<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906" w:h="16838"/>`
+		landscape := `<w:p><w:pPr><w:sectPr><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/>`
+		if !strings.Contains(xml, portrait) || strings.Index(xml, portrait) > strings.Index(xml, landscape) {
+			t.Errorf("第一节应为纵向:\n%s", xml)
+		}
+		if !strings.Contains(xml, `<w:tblW w:w="13958" w:type="dxa"/>`) {
+			t.Errorf("横向节中的表格应使用横向的版心宽度:\n%s", xml)
+		}
+		if !strings.HasSuffix(xml, `<w:sectPr><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr></w:body></w:document>`) {
+			t.Errorf("未指定方向的新节应沿用上一节的方向:\n%s", xml)
+		}
+		if err := wellFormed(xml); err != nil {
+			t.Error(err)
+		}
+
+		var warnings []string
+		g := newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }})
+		g.documentXML(models.Document{Blocks: []models.Block{models.Callout{Kind: "note", Blocks: []models.Block{models.SectionBreak{}}}}})
+		if len(warnings) != 1 || warnings[0] != "忽略表格、引用块、提示块或分栏块中的分节符" {
+			t.Errorf("忽略嵌套的分节符时应给出警告，实际为%q", warnings)
+		}
+	})
+}
+
+func TestColumns(t *testing.T) {
+	paragraph := func(text string) models.Paragraph {
+		return models.Paragraph{Inlines: []models.Inline{models.Text{Content: text}}}
+	}
+	singleColumn := `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr>`
+
+	t.Run("分栏块", func(t *testing.T) {
+		table := models.Table{
+			Columns: []models.TableColumn{{}},
+			Rows:    []models.TableRow{{Cells: []models.TableCell{{Blocks: []models.Block{paragraph("单元格")}}}}},
+		}
+		doc := models.Document{Blocks: []models.Block{
+			paragraph("通栏"),
+			models.Columns{Count: 2, Gap: "10mm", Blocks: []models.Block{paragraph("左栏"), models.ColumnBreak{}, table}},
+			paragraph("恢复通栏"),
+			models.SectionBreak{},
+		}}
+		xml := GenerateDocumentXML(doc)
+		want := `<w:p><w:pPr>` + singleColumn + `</w:pPr></w:p>` +
+			`<w:p><w:pPr><w:rPr></w:rPr></w:pPr><w:r><w:t>左栏</w:t></w:r></w:p><w:p><w:r><w:br w:type="column"/></w:r></w:p>`
+		if !strings.Contains(xml, want) {
+			t.Errorf("分栏块之前应结束通栏的节:\n%s", xml)
+		}
+		if !strings.Contains(xml, `<w:tblW w:w="4229" w:type="dxa"/>`) {
+			t.Errorf("分栏中的表格应使用一栏的宽度:\n%s", xml)
+		}
+		want = `</w:tbl><w:p><w:pPr><w:sectPr><w:type w:val="continuous"/><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>` +
+			`<w:cols w:num="2" w:space="567"/></w:sectPr></w:pPr></w:p>`
+		if !strings.Contains(xml, want) {
+			t.Errorf("分栏的节属性错误:\n%s", xml)
+		}
+		want = `<w:t>恢复通栏</w:t></w:r></w:p><w:p><w:pPr><w:sectPr><w:type w:val="continuous"/><w:pgSz`
+		if !strings.Contains(xml, want) || !strings.HasSuffix(xml, singleColumn+`</w:body></w:document>`) {
+			t.Errorf("分栏块之后应连续排版并恢复通栏，分节符之后的节应另起一页:\n%s", xml)
+		}
+		if err := wellFormed(xml); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("元数据设置正文分栏", func(t *testing.T) {
+		doc := models.Document{
+			Metadata: models.Metadata{Title: "论文标题", Extra: map[string]string{"columns": "2"}},
+			Blocks:   []models.Block{paragraph("正文")},
+		}
+		g := newGenerator(Options{TitleBlock: true})
+		xml := g.documentXML(doc)
+		if !strings.Contains(xml, `<w:t>论文标题</w:t></w:r></w:p><w:p><w:pPr>`+singleColumn+`</w:pPr></w:p><w:p><w:pPr><w:rPr></w:rPr></w:pPr><w:r><w:t>正文</w:t>`) {
+			t.Errorf("标题信息应单独成节，不分栏:\n%s", xml)
+		}
+		if !strings.Contains(xml, `<w:cols w:num="2" w:space="425"/></w:sectPr></w:body>`) {
+			t.Errorf("正文应分两栏:\n%s", xml)
+		}
+
+		g = newGenerator(Options{Columns: 3, ColumnGap: "0.5in"})
+		xml = g.documentXML(doc)
+		if strings.Count(xml, "<w:sectPr>") != 1 || !strings.Contains(xml, `<w:cols w:num="3" w:space="720"/></w:sectPr></w:body>`) {
+			t.Errorf("没有标题信息时整个文档分栏，选项优先于元数据:\n%s", xml)
+		}
+		var warnings []string
+		g = newGenerator(Options{Columns: 2, ColumnGap: "宽", Warn: func(message string) { warnings = append(warnings, message) }})
+		xml = g.documentXML(doc)
+		if !strings.Contains(xml, `<w:cols w:num="2" w:space="425"/>`) || len(warnings) != 1 || warnings[0] != "忽略无效的栏间距: 宽" {
+			t.Errorf("无效的栏间距应使用默认间距并给出警告，实际警告为%q", warnings)
+		}
+	})
+
+	t.Run("嵌套的分栏块", func(t *testing.T) {
+		doc := models.Document{Blocks: []models.Block{
+			models.BlockQuote{Blocks: []models.Block{models.Columns{Count: 2, Blocks: []models.Block{paragraph("引用")}}, models.SectionBreak{}}},
+		}}
+		var warnings []string
+		xml := newGenerator(Options{Warn: func(message string) { warnings = append(warnings, message) }}).documentXML(doc)
+		if strings.Count(xml, "<w:sectPr>") != 1 || !strings.Contains(xml, "引用") {
+			t.Errorf("嵌套的分栏块和分节符不应分节:\n%s", xml)
+		}
+		expected := []string{"表格、引用块、提示块或分栏块中的 2 栏分栏块不分栏", "忽略表格、引用块、提示块或分栏块中的分节符"}
+		if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("嵌套的分栏块和分节符应给出警告，实际为%q", warnings)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// settingsOrder 本程序写入的设置在CT_Settings中的相对顺序，列出其后可能出现的元素以便在参考文档的设置中找到插入位置
+var settingsOrder = []string{
+	"w:evenAndOddHeaders", "w:bookFoldRevPrinting", "w:bookFoldPrinting", "w:bookFoldPrintingSheets",
+	"w:drawingGridHorizontalSpacing", "w:drawingGridVerticalSpacing", "w:displayHorizontalDrawingGridEvery",
+	"w:displayVerticalDrawingGridEvery", "w:doNotUseMarginsForDrawingGridOrigin", "w:drawingGridHorizontalOrigin",
+	"w:drawingGridVerticalOrigin", "w:doNotShadeFormData", "w:noPunctuationKerning", "w:characterSpacingControl",
+	"w:printTwoOnOne", "w:strictFirstAndLastChars", "w:noLineBreaksAfter", "w:noLineBreaksBefore", "w:savePreviewPicture",
+	"w:doNotValidateAgainstSchema", "w:saveInvalidXml", "w:ignoreMixedContent", "w:alwaysShowPlaceholderText",
+	"w:doNotDemarcateInvalidXml", "w:saveXmlDataOnly", "w:useXSLTWhenSaving", "w:saveThroughXslt", "w:showXMLTags",
+	"w:alwaysMergeEmptyNamespace", "w:updateFields", "w:hdrShapeDefaults", "w:footnotePr", "w:endnotePr", "w:compat", "w:docVars", "w:rsids",
+	"m:mathPr", "w:attachedSchema", "w:themeFontLang", "w:clrSchemeMapping", "w:doNotIncludeSubdocsInStats",
+	"w:doNotAutoCompressPictures", "w:forceUpgrade", "w:captions", "w:readModeInkLockDown", "w:smartTagType",
+	"sl:schemaLibrary", "w:shapeDefaults", "w:doNotEmbedSmartTags", "w:decimalSymbol", "w:listSeparator",
+}
+
+// settingsXML 生成settings.xml。包含目录等域时要求Word打开文档时更新域，存在脚注时声明分隔线使用的注释
+func (g *generator) settingsXML() string {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`
+	for _, setting := range g.settings() {
+		xml += setting
+	}
+	return xml + `<w:compat><w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="15"/></w:compat></w:settings>`
+}
+
+// settings 返回文档内容所需的设置，按CT_Settings中的顺序排列
+func (g *generator) settings() []string {
+	var settings []string
+	if g.opts.HeaderFooter.oddEven() {
+		settings = append(settings, `<w:evenAndOddHeaders/>`)
+	}
+	if g.hasFields {
+		settings = append(settings, `<w:updateFields w:val="true"/>`)
+	}
+	if len(g.notes) > 0 {
+		kind := g.opts.noteKind()
+		settings = append(settings, fmt.Sprintf(`<w:%sPr><w:%s w:id="-1"/><w:%s w:id="0"/></w:%sPr>`, kind.element, kind.element, kind.element, kind.element))
+	}
+	return settings
+}
+
+// mergeSettings 将文档内容所需的设置加入参考文档的settings.xml，参考文档中已有的设置保持不变。
+// 没有生成对应的脚注或尾注部件时去掉其中的脚注设置，以免引用不存在的分隔线
+func (g *generator) mergeSettings(settings string) string {
+	for _, element := range []string{"footnote", "endnote"} {
+		if len(g.notes) > 0 && g.opts.noteKind().element == element {
+			continue
+		}
+		start := strings.Index(settings, "<w:"+element+"Pr")
+		end := strings.Index(settings, "</w:"+element+"Pr>")
+		if start != -1 && end > start {
+			settings = settings[:start] + settings[end+len("</w:"+element+"Pr>"):]
+		}
+	}
+	for _, setting := range g.settings() {
+		name := setting[1:strings.IndexAny(setting, " />")]
+		if hasElement(settings, name) {
+			continue
+		}
+		at := strings.LastIndex(settings, "</w:settings>")
+		for i, following := range settingsOrder {
+			if following != name {
+				continue
+			}
+			for _, next := range settingsOrder[i+1:] {
+				if j := elementIndex(settings, next); j != -1 && j < at {
+					at = j
+				}
+			}
+		}
+		if at == -1 {
+			continue
+		}
+		settings = settings[:at] + setting + settings[at:]
+	}
+	return settings
+}
+
+// hasElement 判断XML中是否含有给定名称的元素
+func hasElement(xml, name string) bool {
+	return elementIndex(xml, name) != -1
+}
+
+// elementIndex 返回给定名称的元素第一次出现的位置，不匹配名称以其为前缀的其他元素
+func elementIndex(xml, name string) int {
+	for offset := 0; ; {
+		i := strings.Index(xml[offset:], "<"+name)
+		if i == -1 {
+			return -1
+		}
+		i += offset
+		if end := i + 1 + len(name); end < len(xml) && strings.IndexByte(" \t\r\n/>", xml[end]) != -1 {
+			return i
+		}
+		offset = i + 1
+	}
+}
@@ -0,0 +1,120 @@
+package docx
+
+import (
+	"strings"
+	"unicode"
+
+	"goffice/internal/models"
+)
+
+// documentStats 文档的统计信息，写入app.xml。页数和行数按版心大小和默认字号估算，Word打开文档后会重新计算
+type documentStats struct {
+	pages                int
+	words                int // 字数：每个中日韩文字计为一个字，其他文字以空白分隔计数
+	characters           int // 不含空白的字符数
+	charactersWithSpaces int
+	paragraphs           int
+	lines                int
+}
+
+// documentStats 统计文档中的文字，包括标题信息、表格、引用块、脚注和图题注
+func (g *generator) documentStats(doc models.Document) documentStats {
+	var s documentStats
+	// 一行可容纳的全角字符数和一页的行数
+	size := g.styleSheet().defaultRPr.size
+	charsPerLine := float64(g.section.columnWidth()) / float64(size*10)
+	lineHeight := g.section.linePitch
+	if lineHeight == 0 {
+		lineHeight = size * 10 * 276 / 240
+	}
+	linesPerPage := g.section.textHeight() / lineHeight
+	if g.section.columns > 1 {
+		linesPerPage *= g.section.columns
+	}
+	// 版心过小时每行至少一个字、每页至少一行，避免除以零
+	if charsPerLine < 1 {
+		charsPerLine = 1
+	}
+	if linesPerPage < 1 {
+		linesPerPage = 1
+	}
+	pageLines := 0
+	add := func(text string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		s.paragraphs++
+		width, inWord := 0.0, false
+		for _, r := range text {
+			s.charactersWithSpaces++
+			switch {
+			case unicode.IsSpace(r):
+				inWord = false
+				width += 0.5
+				continue
+			case isEastAsian(r):
+				s.words++
+				inWord = false
+				width++
+			default:
+				if !inWord {
+					s.words++
+				}
+				inWord = true
+				width += 0.5
+			}
+			s.characters++
+		}
+		lines := int(width/charsPerLine) + 1
+		s.lines += lines
+		pageLines += lines
+	}
+	newPage := func() {
+		if pageLines > 0 {
+			s.pages += (pageLines + linesPerPage - 1) / linesPerPage
+			pageLines = 0
+		}
+	}
+
+	if g.opts.TitleBlock {
+		meta := doc.Metadata
+		for _, text := range append([]string{meta.Title, meta.Subtitle, meta.Date}, meta.Authors...) {
+			add(text)
+		}
+		for _, text := range strings.Split(meta.Abstract, "\n\n") {
+			add(text)
+		}
+	}
+	walkBlocks(doc.Blocks, func(block models.Block) {
+		switch b := block.(type) {
+		case models.Header:
+			add(models.PlainText(b.Inlines))
+		case models.Paragraph:
+			add(models.PlainText(b.Inlines))
+		case models.Figure:
+			add(models.PlainText(b.Caption))
+		case models.Callout:
+			add(models.PlainText(b.Title))
+		case models.DefinitionList:
+			for _, item := range b.Items {
+				add(models.PlainText(item.Term))
+			}
+		case models.PageBreak, models.SectionBreak:
+			newPage()
+		}
+	})
+	// 脚注的行数不计入页数
+	newPage()
+	for _, f := range doc.Footnotes {
+		walkBlocks(f.Blocks, func(block models.Block) {
+			if p, ok := block.(models.Paragraph); ok {
+				add(models.PlainText(p.Inlines))
+			}
+		})
+	}
+	if s.pages == 0 {
+		s.pages = 1
+	}
+	return s
+}
@@ -0,0 +1,478 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// styleSheet 描述styles.xml：文档默认格式、隐藏样式的默认设置和各个样式
+type styleSheet struct {
+	defaultRPr runProps       // 所有文本的默认格式
+	defaultPPr paraProps      // 所有段落的默认格式
+	latent     []latentStyle  // Word内置但未在样式表中定义的样式的显示设置
+	styles     []style        // 样式，按写入的顺序排列
+	index      map[string]int // 样式标识符到styles下标的映射
+}
+
+// style 描述一个段落、字符、表格或编号样式
+type style struct {
+	kind       string // paragraph、character、table 或 numbering
+	id         string // 样式标识符，即 pStyle 等引用的值
+	name       string // 样式名，内置样式使用Word的英文名称，以便Word将其识别为内置样式
+	basedOn    string // 所基于的样式
+	next       string // 回车后下一段落使用的样式
+	link       string // 关联的字符或段落样式
+	isDefault  bool   // 是否为该类型的默认样式
+	uiPriority int    // 在样式库中的排序，0表示不设置
+	semiHidden bool   // 是否在样式库中隐藏
+	qFormat    bool   // 是否显示在快速样式库中
+	pPr        paraProps
+	rPr        runProps
+	tblPr      string // 表格属性的XML，仅用于表格样式
+}
+
+// paraProps 段落属性，各字段按CT_PPr中的顺序写入
+type paraProps struct {
+	keepNext   bool
+	keepLines  bool
+	border     string // <w:pBdr> 的内容
+	shading    string // 底纹颜色
+	tabs       string // <w:tabs> 的内容
+	spacing    string // <w:spacing> 的属性，如 w:before="240" w:after="120"
+	ind        string // <w:ind> 的属性
+	jc         string // 对齐方式
+	outlineLvl int    // 大纲级别加1，0表示正文
+}
+
+// runProps 文本属性，各字段按CT_RPr中的顺序写入。样式和文本段都使用它，嵌套的格式逐层设置字段，不拼接XML
+type runProps struct {
+	style     string // 字符样式，仅用于文本段
+	fonts     fonts
+	bold      bool
+	italic    bool
+	color     string
+	size      int    // 字号，单位为半磅，0表示继承
+	highlight string // 突出显示的颜色
+	underline string // 下划线类型
+	vertAlign string // 上标或下标
+	lang      language
+}
+
+// fonts 不同文字使用的字体：ascii和hAnsi用于西文，eastAsia用于中日韩文字，cs用于复杂文种。
+// hint 为 eastAsia 时共用的字符使用东亚字体
+type fonts struct {
+	hint, ascii, hAnsi, eastAsia, cs string
+}
+
+// language 文本的语言，影响拼写检查、断行和标点压缩
+type language struct {
+	val, eastAsia, bidi string
+}
+
+// defaultLanguage 文档默认的语言：西文为美国英语，东亚文字为简体中文
+var defaultLanguage = language{val: "en-US", eastAsia: "zh-CN", bidi: "ar-SA"}
+
+// languageRegions 只有语言代码时使用的默认地区
+var languageRegions = map[string]string{"zh": "zh-CN", "ja": "ja-JP", "ko": "ko-KR", "en": "en-US", "fr": "fr-FR", "de": "de-DE"}
+
+// documentLanguage 由语言标签确定文档的默认语言：中日韩语言写入 w:eastAsia，其他语言写入 w:val
+func documentLanguage(tag string) language {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if full, ok := languageRegions[strings.ToLower(tag)]; ok {
+		tag = full
+	}
+	lang := defaultLanguage
+	switch strings.ToLower(strings.SplitN(tag, "-", 2)[0]) {
+	case "":
+	case "zh", "ja", "ko":
+		lang.eastAsia = tag
+	default:
+		lang.val = tag
+	}
+	return lang
+}
+
+// latentStyle 隐藏样式的例外设置
+type latentStyle struct {
+	name           string
+	uiPriority     int
+	semiHidden     bool
+	unhideWhenUsed bool
+	qFormat        bool
+}
+
+// defaultStyleSheet 返回默认的样式表，包含生成的文档用到的所有样式
+func defaultStyleSheet() *styleSheet {
+	sheet := &styleSheet{
+		defaultRPr: runProps{
+			fonts: fonts{ascii: "Times New Roman", hAnsi: "Times New Roman", eastAsia: "宋体", cs: "Times New Roman"},
+			size:  24,
+			lang:  defaultLanguage,
+		},
+		defaultPPr: paraProps{spacing: `w:after="120" w:line="276" w:lineRule="auto"`},
+		index:      map[string]int{},
+	}
+
+	sheet.latent = append(sheet.latent,
+		latentStyle{name: "Normal", qFormat: true},
+		latentStyle{name: "Title", uiPriority: 10, qFormat: true},
+		latentStyle{name: "Subtitle", uiPriority: 11, qFormat: true},
+		latentStyle{name: "Strong", uiPriority: 22, qFormat: true},
+		latentStyle{name: "Emphasis", uiPriority: 20, qFormat: true},
+		latentStyle{name: "Quote", uiPriority: 29, qFormat: true},
+		latentStyle{name: "caption", uiPriority: 35, semiHidden: true, unhideWhenUsed: true, qFormat: true},
+		latentStyle{name: "Table Grid", uiPriority: 59},
+		latentStyle{name: "TOC Heading", uiPriority: 39, semiHidden: true, unhideWhenUsed: true, qFormat: true},
+	)
+	for level := 1; level <= 9; level++ {
+		heading := latentStyle{name: fmt.Sprintf("heading %d", level), uiPriority: 9, qFormat: true}
+		if level > 1 {
+			heading.semiHidden, heading.unhideWhenUsed = true, true
+		}
+		sheet.latent = append(sheet.latent, heading,
+			latentStyle{name: fmt.Sprintf("toc %d", level), uiPriority: 39, semiHidden: true, unhideWhenUsed: true})
+	}
+
+	sheet.add(style{kind: "paragraph", id: "Normal", name: "Normal", isDefault: true, qFormat: true})
+	sheet.add(style{kind: "character", id: "DefaultParagraphFont", name: "Default Paragraph Font", isDefault: true, uiPriority: 1, semiHidden: true})
+	sheet.add(style{kind: "table", id: "TableNormal", name: "Normal Table", isDefault: true, uiPriority: 99, semiHidden: true,
+		tblPr: `<w:tblInd w:w="0" w:type="dxa"/><w:tblCellMar><w:top w:w="0" w:type="dxa"/><w:left w:w="108" w:type="dxa"/>` +
+			`<w:bottom w:w="0" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar>`})
+	sheet.add(style{kind: "numbering", id: "NoList", name: "No List", isDefault: true, uiPriority: 99, semiHidden: true})
+
+	// 标题逐级减小字号，五级以下用斜体区分
+	headingSizes := []int{36, 32, 28, 26, 24, 24, 22, 22, 22}
+	headingSpacing := []string{`w:before="480" w:after="240"`, `w:before="360" w:after="180"`, `w:before="240" w:after="120"`}
+	for level := 1; level <= 9; level++ {
+		spacing := headingSpacing[len(headingSpacing)-1]
+		if level <= len(headingSpacing) {
+			spacing = headingSpacing[level-1]
+		}
+		sheet.add(style{kind: "paragraph", id: fmt.Sprintf("Heading%d", level), name: fmt.Sprintf("heading %d", level),
+			basedOn: "Normal", next: "Normal", uiPriority: 9, qFormat: true,
+			pPr: paraProps{keepNext: true, keepLines: true, spacing: spacing, outlineLvl: level},
+			rPr: runProps{fonts: fonts{eastAsia: "黑体"}, bold: true, italic: level >= 5, size: headingSizes[level-1]}})
+	}
+
+	sheet.add(style{kind: "paragraph", id: "Title", name: "Title", basedOn: "Normal", next: "Normal", uiPriority: 10, qFormat: true,
+		pPr: paraProps{spacing: `w:before="480" w:after="240"`, jc: "center"}, rPr: runProps{fonts: fonts{eastAsia: "黑体"}, bold: true, size: 40}})
+	sheet.add(style{kind: "paragraph", id: "Subtitle", name: "Subtitle", basedOn: "Normal", next: "Normal", uiPriority: 11, qFormat: true,
+		pPr: paraProps{spacing: `w:after="240"`, jc: "center"}, rPr: runProps{size: 30}})
+	sheet.add(style{kind: "paragraph", id: "Author", name: "Author", basedOn: "Normal", next: "Normal", qFormat: true,
+		pPr: paraProps{jc: "center"}})
+	sheet.add(style{kind: "paragraph", id: "Date", name: "Date", basedOn: "Normal", next: "Normal", qFormat: true,
+		pPr: paraProps{spacing: `w:after="240"`, jc: "center"}})
+	sheet.add(style{kind: "paragraph", id: "Abstract", name: "Abstract", basedOn: "Normal", next: "Normal", qFormat: true,
+		pPr: paraProps{spacing: `w:before="120" w:after="120"`, ind: `w:left="720" w:right="720"`}, rPr: runProps{size: 20}})
+	sheet.add(style{kind: "paragraph", id: "Quote", name: "Quote", basedOn: "Normal", next: "Normal", uiPriority: 29, qFormat: true,
+		pPr: paraProps{border: `<w:left w:val="single" w:sz="18" w:space="8" w:color="BFBFBF"/>`, spacing: `w:before="120" w:after="120"`, ind: `w:left="567"`},
+		rPr: runProps{color: "595959"}})
+	sheet.add(style{kind: "paragraph", id: "DefinitionTerm", name: "Definition Term", basedOn: "Normal", next: "Definition",
+		pPr: paraProps{keepNext: true, spacing: `w:before="120" w:after="0"`}, rPr: runProps{bold: true}})
+	sheet.add(style{kind: "paragraph", id: "Definition", name: "Definition", basedOn: "Normal",
+		pPr: paraProps{spacing: `w:after="120"`, ind: `w:left="720"`}})
+	sheet.add(style{kind: "paragraph", id: "Caption", name: "caption", basedOn: "Normal", next: "Normal", uiPriority: 35, qFormat: true,
+		pPr: paraProps{spacing: `w:before="60" w:after="240"`, jc: "center"}, rPr: runProps{size: 18}})
+
+	// 目录各级缩进11磅，页码右对齐并以点线引导
+	tocTabs := tocTab(defaultTextWidth)
+	sheet.add(style{kind: "paragraph", id: "TOCHeading", name: "TOC Heading", basedOn: "Heading1", next: "Normal", uiPriority: 39, qFormat: true,
+		pPr: paraProps{outlineLvl: 10}})
+	for level := 1; level <= 9; level++ {
+		toc := style{kind: "paragraph", id: fmt.Sprintf("TOC%d", level), name: fmt.Sprintf("toc %d", level), basedOn: "Normal", next: "Normal", uiPriority: 39,
+			pPr: paraProps{tabs: tocTabs, spacing: `w:after="100"`}}
+		if level > 1 {
+			toc.pPr.ind = fmt.Sprintf(`w:left="%d"`, 220*(level-1))
+		}
+		sheet.add(toc)
+	}
+	sheet.add(style{kind: "paragraph", id: "TableofFigures", name: "table of figures", basedOn: "Normal", next: "Normal",
+		pPr: paraProps{tabs: tocTabs}})
+
+	sheet.add(style{kind: "character", id: "Hyperlink", name: "Hyperlink", basedOn: "DefaultParagraphFont", uiPriority: 99,
+		rPr: runProps{color: "0563C1", underline: "single"}})
+	code := fonts{ascii: "Consolas", hAnsi: "Consolas", cs: "Consolas"}
+	sheet.add(style{kind: "paragraph", id: "SourceCode", name: "Source Code", basedOn: "Normal", link: "VerbatimChar",
+		pPr: paraProps{shading: "F5F5F5", spacing: `w:before="0" w:after="0" w:line="240" w:lineRule="auto"`}, rPr: runProps{fonts: code, size: 20}})
+	sheet.add(style{kind: "character", id: "VerbatimChar", name: "Verbatim Char", basedOn: "DefaultParagraphFont", link: "SourceCode",
+		rPr: runProps{fonts: code, size: 20}})
+	for _, note := range []string{"footnote", "endnote"} {
+		id := strings.ToUpper(note[:1]) + note[1:]
+		sheet.add(style{kind: "paragraph", id: id + "Text", name: note + " text", basedOn: "Normal", uiPriority: 99, semiHidden: true,
+			pPr: paraProps{spacing: `w:after="0" w:line="240" w:lineRule="auto"`}, rPr: runProps{size: 18}})
+		sheet.add(style{kind: "character", id: id + "Reference", name: note + " reference", basedOn: "DefaultParagraphFont", uiPriority: 99, semiHidden: true,
+			rPr: runProps{vertAlign: "superscript"}})
+	}
+
+	// 页眉页脚可以用制表符分为左、中、右三部分
+	for _, id := range []string{"Header", "Footer"} {
+		sheet.add(style{kind: "paragraph", id: id, name: strings.ToLower(id), basedOn: "Normal", uiPriority: 99, semiHidden: true,
+			pPr: paraProps{tabs: headerTabs(defaultTextWidth), spacing: `w:after="0" w:line="240" w:lineRule="auto"`}, rPr: runProps{size: 18}})
+	}
+
+	sheet.add(style{kind: "paragraph", id: "CommentText", name: "annotation text", basedOn: "Normal", uiPriority: 99, semiHidden: true,
+		pPr: paraProps{spacing: `w:after="0" w:line="240" w:lineRule="auto"`}, rPr: runProps{size: 20}})
+	sheet.add(style{kind: "character", id: "CommentReference", name: "annotation reference", basedOn: "DefaultParagraphFont", uiPriority: 99, semiHidden: true,
+		rPr: runProps{size: 16}})
+
+	border := `w:val="single" w:sz="4" w:space="0" w:color="auto"`
+	sheet.add(style{kind: "table", id: "TableGrid", name: "Table Grid", basedOn: "TableNormal", uiPriority: 59,
+		pPr: paraProps{spacing: `w:after="0" w:line="240" w:lineRule="auto"`},
+		tblPr: `<w:tblBorders><w:top ` + border + `/><w:left ` + border + `/><w:bottom ` + border + `/><w:right ` + border + `/>` +
+			`<w:insideH ` + border + `/><w:insideV ` + border + `/></w:tblBorders>`})
+	return sheet
+}
+
+// add 添加样式，已有同一标识符的样式时替换之
+func (s *styleSheet) add(st style) {
+	if i, ok := s.index[st.id]; ok {
+		s.styles[i] = st
+		return
+	}
+	s.index[st.id] = len(s.styles)
+	s.styles = append(s.styles, st)
+}
+
+// style 返回给定标识符的样式，用于修改其格式；样式不存在时返回nil
+func (s *styleSheet) style(id string) *style {
+	if i, ok := s.index[id]; ok {
+		return &s.styles[i]
+	}
+	return nil
+}
+
+// XML 生成styles.xml的内容
+func (s *styleSheet) XML() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+    <w:docDefaults><w:rPrDefault><w:rPr>` + s.defaultRPr.XML() + `</w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr>` + s.defaultPPr.XML() + `</w:pPr></w:pPrDefault></w:docDefaults>`)
+	b.WriteString("\n    " + `<w:latentStyles w:defLockedState="0" w:defUIPriority="99" w:defSemiHidden="0" w:defUnhideWhenUsed="0" w:defQFormat="0" w:count="376">`)
+	for _, l := range s.latent {
+		b.WriteString(l.XML())
+	}
+	b.WriteString(`</w:latentStyles>`)
+	for _, st := range s.styles {
+		b.WriteString("\n    " + st.XML())
+	}
+	b.WriteString("\n</w:styles>")
+	return b.String()
+}
+
+// XML 生成 <w:style> 元素，子元素按CT_Style中的顺序写入
+func (st style) XML() string {
+	xml := `<w:style w:type="` + st.kind + `"`
+	if st.isDefault {
+		xml += ` w:default="1"`
+	}
+	xml += ` w:styleId="` + escapeXML(st.id) + `"><w:name w:val="` + escapeXML(st.name) + `"/>`
+	if st.basedOn != "" {
+		xml += `<w:basedOn w:val="` + st.basedOn + `"/>`
+	}
+	if st.next != "" {
+		xml += `<w:next w:val="` + st.next + `"/>`
+	}
+	if st.link != "" {
+		xml += `<w:link w:val="` + st.link + `"/>`
+	}
+	if st.uiPriority != 0 {
+		xml += fmt.Sprintf(`<w:uiPriority w:val="%d"/>`, st.uiPriority)
+	}
+	if st.semiHidden {
+		xml += `<w:semiHidden/><w:unhideWhenUsed/>`
+	}
+	if st.qFormat {
+		xml += `<w:qFormat/>`
+	}
+	if pPr := st.pPr.XML(); pPr != "" {
+		xml += `<w:pPr>` + pPr + `</w:pPr>`
+	}
+	if rPr := st.rPr.XML(); rPr != "" {
+		xml += `<w:rPr>` + rPr + `</w:rPr>`
+	}
+	if st.tblPr != "" {
+		xml += `<w:tblPr>` + st.tblPr + `</w:tblPr>`
+	}
+	return xml + `</w:style>`
+}
+
+// XML 生成段落属性的各个子元素
+func (p paraProps) XML() string {
+	xml := ""
+	if p.keepNext {
+		xml += `<w:keepNext/>`
+	}
+	if p.keepLines {
+		xml += `<w:keepLines/>`
+	}
+	if p.border != "" {
+		xml += `<w:pBdr>` + p.border + `</w:pBdr>`
+	}
+	if p.shading != "" {
+		xml += `<w:shd w:val="clear" w:color="auto" w:fill="` + p.shading + `"/>`
+	}
+	if p.tabs != "" {
+		xml += `<w:tabs>` + p.tabs + `</w:tabs>`
+	}
+	if p.spacing != "" {
+		xml += `<w:spacing ` + p.spacing + `/>`
+	}
+	if p.ind != "" {
+		xml += `<w:ind ` + p.ind + `/>`
+	}
+	if p.jc != "" {
+		xml += `<w:jc w:val="` + p.jc + `"/>`
+	}
+	if p.outlineLvl > 0 {
+		xml += fmt.Sprintf(`<w:outlineLvl w:val="%d"/>`, p.outlineLvl-1)
+	}
+	return xml
+}
+
+// XML 生成文本属性的各个子元素
+func (r runProps) XML() string {
+	xml := ""
+	if r.style != "" {
+		xml += `<w:rStyle w:val="` + r.style + `"/>`
+	}
+	if f := r.fonts; f != (fonts{}) {
+		xml += `<w:rFonts`
+		for _, attr := range [][2]string{{"hint", f.hint}, {"ascii", f.ascii}, {"hAnsi", f.hAnsi}, {"eastAsia", f.eastAsia}, {"cs", f.cs}} {
+			if attr[1] != "" {
+				xml += ` w:` + attr[0] + `="` + escapeXML(attr[1]) + `"`
+			}
+		}
+		xml += `/>`
+	}
+	if r.bold {
+		xml += `<w:b/><w:bCs/>`
+	}
+	if r.italic {
+		xml += `<w:i/><w:iCs/>`
+	}
+	if r.color != "" {
+		xml += `<w:color w:val="` + r.color + `"/>`
+	}
+	if r.size != 0 {
+		xml += fmt.Sprintf(`<w:sz w:val="%d"/><w:szCs w:val="%d"/>`, r.size, r.size)
+	}
+	if r.highlight != "" {
+		xml += `<w:highlight w:val="` + r.highlight + `"/>`
+	}
+	if r.underline != "" {
+		xml += `<w:u w:val="` + r.underline + `"/>`
+	}
+	if r.vertAlign != "" {
+		xml += `<w:vertAlign w:val="` + r.vertAlign + `"/>`
+	}
+	if l := r.lang; l != (language{}) {
+		xml += `<w:lang`
+		for _, attr := range [][2]string{{"val", l.val}, {"eastAsia", l.eastAsia}, {"bidi", l.bidi}} {
+			if attr[1] != "" {
+				xml += ` w:` + attr[0] + `="` + attr[1] + `"`
+			}
+		}
+		xml += `/>`
+	}
+	return xml
+}
+
+// XML 生成 <w:lsdException> 元素
+func (l latentStyle) XML() string {
+	xml := `<w:lsdException w:name="` + l.name + `"`
+	if l.uiPriority != 0 {
+		xml += fmt.Sprintf(` w:uiPriority="%d"`, l.uiPriority)
+	}
+	if l.semiHidden {
+		xml += ` w:semiHidden="1"`
+	}
+	if l.unhideWhenUsed {
+		xml += ` w:unhideWhenUsed="1"`
+	}
+	if l.qFormat {
+		xml += ` w:qFormat="1"`
+	}
+	return xml + `/>`
+}
+
+// tocTab 返回目录中右对齐页码的制表位，pos为版心宽度
+func tocTab(pos int) string {
+	return fmt.Sprintf(`<w:tab w:val="right" w:leader="dot" w:pos="%d"/>`, pos)
+}
+
+// headerTabs 返回页眉页脚中居中和右对齐的制表位，pos为版心宽度
+func headerTabs(pos int) string {
+	return fmt.Sprintf(`<w:tab w:val="center" w:pos="%d"/><w:tab w:val="right" w:pos="%d"/>`, pos/2, pos)
+}
+
+// headingStyleIDs 使用标题字体的样式
+var headingStyleIDs = []string{"Title", "Heading1", "Heading2", "Heading3", "Heading4", "Heading5", "Heading6", "Heading7", "Heading8", "Heading9"}
+
+// styleSheet 返回按版式预设和选项设置字体、语言后的默认样式表
+func (g *generator) styleSheet() *styleSheet {
+	sheet := defaultStyleSheet()
+	if p, ok := presets[g.opts.Preset]; ok && p.styles != nil {
+		p.styles(sheet)
+	}
+	// 目录的页码对齐版心右边缘
+	for _, id := range []string{"TOC1", "TOC2", "TOC3", "TOC4", "TOC5", "TOC6", "TOC7", "TOC8", "TOC9", "TableofFigures"} {
+		sheet.style(id).pPr.tabs = tocTab(g.section.textWidth())
+	}
+	sheet.style("Header").pPr.tabs = headerTabs(g.section.textWidth())
+	sheet.style("Footer").pPr.tabs = headerTabs(g.section.textWidth())
+	f := g.opts.Fonts
+	if f.Latin != "" {
+		sheet.defaultRPr.fonts.ascii, sheet.defaultRPr.fonts.hAnsi, sheet.defaultRPr.fonts.cs = f.Latin, f.Latin, f.Latin
+	}
+	if f.EastAsian != "" {
+		sheet.defaultRPr.fonts.eastAsia = f.EastAsian
+	}
+	for _, id := range headingStyleIDs {
+		st := sheet.style(id)
+		if f.HeadingLatin != "" {
+			st.rPr.fonts.ascii, st.rPr.fonts.hAnsi, st.rPr.fonts.cs = f.HeadingLatin, f.HeadingLatin, f.HeadingLatin
+		}
+		if f.HeadingEastAsian != "" {
+			st.rPr.fonts.eastAsia = f.HeadingEastAsian
+		}
+	}
+	sheet.defaultRPr.lang = documentLanguage(g.lang)
+	return sheet
+}
+
+// stylesXML 返回styles.xml的内容。使用参考文档时保留其样式，并补充生成的内容用到而参考文档中没有的样式；
+// 选项中的字体、语言和预设的样式不用于参考文档的样式，给出警告
+func (g *generator) stylesXML() string {
+	sheet := g.styleSheet()
+	if g.reference == nil {
+		return sheet.XML()
+	}
+	var ignored []string
+	if g.opts.Preset != "" {
+		ignored = append(ignored, "版式预设 "+g.opts.Preset+" 的样式")
+	}
+	if g.opts.Fonts != (Fonts{}) {
+		ignored = append(ignored, "字体")
+	}
+	if g.opts.Lang != "" {
+		ignored = append(ignored, "文档语言")
+	}
+	if len(ignored) > 0 {
+		g.warn("使用参考文档中的样式，忽略%s", strings.Join(ignored, "、"))
+	}
+	styles := g.reference.styles
+	end := strings.LastIndex(styles, "</w:styles>")
+	if end == -1 {
+		return styles
+	}
+	var missing string
+	for _, st := range sheet.styles {
+		if !strings.Contains(styles, `w:styleId="`+st.id+`"`) {
+			// 参考文档已有各类型的默认样式，补充的样式不能再作为默认样式
+			st.isDefault = false
+			missing += st.XML()
+		}
+	}
+	return styles[:end] + missing + styles[end:]
+}
@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestStyleSheet(t *testing.T) {
+	styles := defaultStyleSheet().XML()
+
+	t.Run("格式良好", func(t *testing.T) {
+		if err := wellFormed(styles); err != nil {
+			t.Fatalf("styles.xml 格式不正确: %v", err)
+		}
+		if !strings.Contains(styles, `<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Times New Roman"`) ||
+			!strings.Contains(styles, `<w:latentStyles `) {
+			t.Error("缺少文档默认格式或隐藏样式设置")
+		}
+	})
+
+	t.Run("样式完整且不重复", func(t *testing.T) {
+		ids := map[string]int{}
+		for _, m := range regexp.MustCompile(`w:styleId="([^"]+)"`).FindAllStringSubmatch(styles, -1) {
+			ids[m[1]]++
+		}
+		for _, id := range []string{"Normal", "Heading4", "Heading9", "Title", "Subtitle", "Quote", "Caption", "Hyperlink",
+			"VerbatimChar", "SourceCode", "TableNormal", "TableGrid", "TOC9"} {
+			if ids[id] != 1 {
+				t.Errorf("样式 %s 出现 %d 次", id, ids[id])
+			}
+		}
+	})
+
+	t.Run("文档引用的样式都有定义", func(t *testing.T) {
+		doc := models.Document{
+			Metadata: models.Metadata{Title: "标题", Subtitle: "副标题", Authors: []string{"作者"}, Date: "2024", Abstract: "摘要"},
+			Blocks: []models.Block{
+				models.Header{Level: 6, Inlines: []models.Inline{models.Text{Content: "六级标题"}}},
+				models.Paragraph{Inlines: []models.Inline{
+					models.Code{Content: "code"},
+					models.Link{URL: "https://go.dev", Content: []models.Inline{models.Text{Content: "链接"}}},
+				}},
+				models.BlockQuote{Blocks: []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: "引用"}}}}},
+				models.Table{Head: []models.TableRow{{Cells: []models.TableCell{{Blocks: []models.Block{models.Paragraph{}}}}}}},
+				models.TableOfContents{},
+				models.ListOfFigures{},
+			},
+		}
+		xml := newGenerator(Options{TitleBlock: true}).documentXML(doc)
+		for _, m := range regexp.MustCompile(`w:(?:pStyle|rStyle|tblStyle) w:val="([^"]+)"`).FindAllStringSubmatch(xml, -1) {
+			if !strings.Contains(styles, `w:styleId="`+m[1]+`"`) {
+				t.Errorf("文档引用了未定义的样式 %s", m[1])
+			}
+		}
+	})
+
+	t.Run("修改样式", func(t *testing.T) {
+		sheet := defaultStyleSheet()
+		sheet.style("Heading1").rPr.color = "C00000"
+		if !strings.Contains(sheet.XML(), `<w:color w:val="C00000"/>`) {
+			t.Error("修改后的样式未写入")
+		}
+		if sheet.style("NoSuchStyle") != nil {
+			t.Error("不存在的样式应返回nil")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// defaultTextWidth A4纸默认页边距下的版心宽度（单位：twip）
+const defaultTextWidth = 9026
+
+// textWidth 返回当前节的版心宽度，分栏时为一栏的宽度
+func (g *generator) textWidth() int {
+	return g.section.columnWidth()
+}
+
+// tablePlacement 记录单元格在表格网格中的位置
+type tablePlacement struct {
+	cell     models.TableCell
+	row, col int
+	colSpan  int
+	rowSpan  int
+}
+
+// tableXML 将表格转换为XML，跨列使用gridSpan，跨行使用vMerge
+func (g *generator) tableXML(t models.Table) string {
+	rows := append(append([]models.TableRow{}, t.Head...), t.Rows...)
+	cols := len(t.Columns)
+	for _, row := range rows {
+		n := 0
+		for _, cell := range row.Cells {
+			span, _ := cell.Span()
+			n += span
+		}
+		if n > cols {
+			cols = n
+		}
+	}
+	if cols == 0 {
+		return ""
+	}
+	widths := columnWidths(t.Columns, cols, g.textWidth())
+	grid := layoutTable(rows, cols)
+
+	var b strings.Builder
+	b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/>`)
+	fmt.Fprintf(&b, `<w:tblW w:w="%d" w:type="dxa"/>`, sum(widths))
+	b.WriteString(`<w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		fmt.Fprintf(&b, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="auto"/>`, side)
+	}
+	b.WriteString(`</w:tblBorders><w:tblLayout w:type="fixed"/>`)
+	b.WriteString(`<w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar>`)
+	b.WriteString(`</w:tblPr><w:tblGrid>`)
+	for _, w := range widths {
+		fmt.Fprintf(&b, `<w:gridCol w:w="%d"/>`, w)
+	}
+	b.WriteString(`</w:tblGrid>`)
+
+	for r := range rows {
+		b.WriteString(`<w:tr>`)
+		if r < len(t.Head) {
+			b.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+		}
+		for c := 0; c < cols; {
+			p := grid[r][c]
+			if p == nil {
+				// 行内单元格不足时补齐空单元格
+				fmt.Fprintf(&b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/></w:tcPr><w:p/></w:tc>`, widths[c])
+				c++
+				continue
+			}
+			b.WriteString(`<w:tc><w:tcPr>`)
+			fmt.Fprintf(&b, `<w:tcW w:w="%d" w:type="dxa"/>`, sum(widths[p.col:p.col+p.colSpan]))
+			if p.colSpan > 1 {
+				fmt.Fprintf(&b, `<w:gridSpan w:val="%d"/>`, p.colSpan)
+			}
+			if p.row != r {
+				b.WriteString(`<w:vMerge/></w:tcPr><w:p/></w:tc>`)
+			} else {
+				if p.rowSpan > 1 {
+					b.WriteString(`<w:vMerge w:val="restart"/>`)
+				}
+				b.WriteString(`</w:tcPr>`)
+				b.WriteString(g.cellContentXML(p.cell, columnAlign(t.Columns, p.col)))
+				b.WriteString(`</w:tc>`)
+			}
+			c += p.colSpan
+		}
+		b.WriteString(`</w:tr>`)
+	}
+	b.WriteString(`</w:tbl>`)
+	return b.String()
+}
+
+// layoutTable 按行放置单元格，跳过被上方跨行单元格占据的位置
+func layoutTable(rows []models.TableRow, cols int) [][]*tablePlacement {
+	grid := make([][]*tablePlacement, len(rows))
+	for r := range grid {
+		grid[r] = make([]*tablePlacement, cols)
+	}
+	for r, row := range rows {
+		c := 0
+		for _, cell := range row.Cells {
+			for c < cols && grid[r][c] != nil {
+				c++
+			}
+			if c >= cols {
+				break
+			}
+			colSpan, rowSpan := cell.Span()
+			// 跨度不能超出表格，也不能覆盖已放置的单元格
+			span := 0
+			for span < colSpan && c+span < cols && grid[r][c+span] == nil {
+				span++
+			}
+			if r+rowSpan > len(rows) {
+				rowSpan = len(rows) - r
+			}
+			p := &tablePlacement{cell: cell, row: r, col: c, colSpan: span, rowSpan: 1}
+			for y := r; y < r+rowSpan; y++ {
+				free := true
+				for x := c; x < c+span; x++ {
+					if grid[y][x] != nil {
+						free = false
+					}
+				}
+				if !free {
+					break
+				}
+				for x := c; x < c+span; x++ {
+					grid[y][x] = p
+				}
+				p.rowSpan = y - r + 1
+			}
+			c += span
+		}
+	}
+	return grid
+}
+
+// cellContentXML 生成单元格内容，单元格内至少需要一个段落
+func (g *generator) cellContentXML(cell models.TableCell, align string) string {
+	props := ""
+	if align != "" {
+		props = `<w:jc w:val="` + align + `"/>`
+	}
+	if len(cell.Blocks) == 0 {
+		return `<w:p><w:pPr>` + props + `</w:pPr></w:p>`
+	}
+	xml := ""
+	for _, block := range cell.Blocks {
+		if p, ok := block.(models.Paragraph); ok {
+			xml += g.paragraphXML(p, props)
+		} else {
+			xml += g.blockXML(block)
+		}
+	}
+	return xml
+}
+
+// columnWidths 按相对宽度分配列宽，未指定宽度的列平分剩余宽度
+func columnWidths(columns []models.TableColumn, cols, total int) []int {
+	widths := make([]int, cols)
+	specified := 0.0
+	auto := 0
+	for c := 0; c < cols; c++ {
+		if c < len(columns) && columns[c].Width > 0 {
+			specified += columns[c].Width
+		} else {
+			auto++
+		}
+	}
+	remaining := 0.0
+	if auto > 0 {
+		remaining = (1 - specified) / float64(auto)
+		if remaining <= 0 {
+			remaining = 1 / float64(cols)
+		}
+	}
+	scale := specified + remaining*float64(auto)
+	if scale == 0 {
+		scale = 1
+	}
+	for c := 0; c < cols; c++ {
+		w := remaining
+		if c < len(columns) && columns[c].Width > 0 {
+			w = columns[c].Width
+		}
+		widths[c] = int(math.Round(w / scale * float64(total)))
+	}
+	return widths
+}
+
+// columnAlign 将列对齐方式转换为w:jc的取值
+func columnAlign(columns []models.TableColumn, col int) string {
+	if col >= len(columns) {
+		return ""
+	}
+	switch columns[col].Align {
+	case "left", "center", "right":
+		return columns[col].Align
+	}
+	return ""
+}
+
+// sum 计算整数切片之和
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+// textCell 创建只包含一段文本的单元格
+func textCell(text string, colSpan, rowSpan int) models.TableCell {
+	return models.TableCell{
+		Blocks:  []models.Block{models.Paragraph{Inlines: []models.Inline{models.Text{Content: text}}}},
+		ColSpan: colSpan,
+		RowSpan: rowSpan,
+	}
+}
+
+func TestTableXML(t *testing.T) {
+	table := models.Table{
+		Columns: []models.TableColumn{{Align: "center", Width: 0.5}, {Width: 0.25}, {}},
+		Head: []models.TableRow{
+			{Cells: []models.TableCell{textCell("A", 1, 1), textCell("B", 2, 1)}},
+		},
+		Rows: []models.TableRow{
+			{Cells: []models.TableCell{textCell("跨行", 1, 2), textCell("x", 1, 1), textCell("y", 1, 1)}},
+			{Cells: []models.TableCell{textCell("z", 1, 1), textCell("w", 1, 1)}},
+		},
+	}
+	xml := newGenerator(Options{}).tableXML(table)
+
+	if strings.Count(xml, "<w:gridCol ") != 3 {
+		t.Errorf("期望生成3个gridCol，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:gridCol w:w="4513"/><w:gridCol w:w="2257"/><w:gridCol w:w="2257"/>`) {
+		t.Errorf("列宽分配错误，实际XML为:\n%s", xml)
+	}
+	if !strings.Contains(xml, `<w:tcW w:w="4514" w:type="dxa"/><w:gridSpan w:val="2"/>`) {
+		t.Error("跨列单元格缺少gridSpan或宽度错误")
+	}
+	if !strings.Contains(xml, `<w:vMerge w:val="restart"/>`) {
+		t.Error("跨行单元格缺少vMerge起始标记")
+	}
+	if !strings.Contains(xml, `<w:vMerge/></w:tcPr><w:p/></w:tc>`) {
+		t.Error("被跨行占据的位置缺少vMerge延续单元格")
+	}
+	if strings.Count(xml, "<w:tc>") != 8 {
+		t.Errorf("期望生成8个单元格，实际为%d", strings.Count(xml, "<w:tc>"))
+	}
+	if !strings.Contains(xml, `<w:tblHeader/>`) {
+		t.Error("表头行缺少tblHeader标记")
+	}
+	if !strings.Contains(xml, `<w:jc w:val="center"/>`) {
+		t.Error("居中列的段落缺少对齐属性")
+	}
+}
@@ -0,0 +1,62 @@
+package docx
+
+import "fmt"
+
+// tocLevels 目录中包含的标题级别
+const tocLevels = 3
+
+// fieldParagraph 域结果中的一个段落
+type fieldParagraph struct {
+	style   string // 段落样式
+	content string // 段落中的文本段
+}
+
+// complexFieldXML 生成跨越若干段落的复杂域，instr为域代码（不含需要转义的字符），placeholder为Word更新域之前显示的结果，至少有一个段落。
+// 域的开始标记位于第一个段落，结束标记位于最后一个段落
+func (g *generator) complexFieldXML(instr string, placeholder []fieldParagraph) string {
+	g.hasFields = true
+	xml := ""
+	for i, p := range placeholder {
+		xml += `<w:p><w:pPr><w:pStyle w:val="` + p.style + `"/></w:pPr>`
+		if i == 0 {
+			xml += `<w:r><w:fldChar w:fldCharType="begin"/></w:r>` +
+				`<w:r><w:instrText xml:space="preserve"> ` + instr + ` </w:instrText></w:r>` +
+				`<w:r><w:fldChar w:fldCharType="separate"/></w:r>`
+		}
+		xml += p.content
+		if i == len(placeholder)-1 {
+			xml += `<w:r><w:fldChar w:fldCharType="end"/></w:r>`
+		}
+		xml += `</w:p>`
+	}
+	return xml
+}
+
+// fieldEntryXML 生成目录或图目录中指向书签的条目
+func fieldEntryXML(bookmark, text string) string {
+	return `<w:hyperlink w:anchor="` + bookmark + `" w:history="1">` +
+		`<w:r><w:t xml:space="preserve">` + escapeXML(text) + `</w:t></w:r></w:hyperlink>`
+}
+
+// tocXML 生成目录，即 TOC 域，并预先填入各级标题，使文档在Word更新域之前也能显示目录
+func (g *generator) tocXML() string {
+	var entries []fieldParagraph
+	for _, entry := range g.headings {
+		if entry.level > tocLevels {
+			continue
+		}
+		text := entry.text
+		if entry.number != "" {
+			separator := " "
+			if g.numberFormats[entry.level-1].suffix == "nothing" {
+				separator = ""
+			}
+			text = entry.number + separator + text
+		}
+		entries = append(entries, fieldParagraph{style: fmt.Sprintf("TOC%d", entry.level), content: fieldEntryXML(entry.bookmark, text)})
+	}
+	if len(entries) == 0 {
+		entries = []fieldParagraph{{style: "TOC1"}}
+	}
+	return g.complexFieldXML(fmt.Sprintf(`TOC \o "1-%d" \h \z \u`, tocLevels), entries)
+}
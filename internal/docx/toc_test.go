@@ -0,0 +1,64 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestTOCXML(t *testing.T) {
+	heading := func(level int, text string) models.Header {
+		return models.Header{Level: level, Inlines: []models.Inline{models.Text{Content: text}}}
+	}
+	doc := models.Document{Blocks: []models.Block{
+		models.TableOfContents{},
+		heading(1, "简介"),
+		heading(2, "背景 & 目标"),
+		heading(4, "细节"),
+	}}
+	g := newGenerator(Options{})
+	xml := g.documentXML(doc)
+
+	for _, want := range []string{
+		`<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> TOC \o "1-3" \h \z \u </w:instrText></w:r>`,
+		`<w:hyperlink w:anchor="简介" w:history="1"><w:r><w:t xml:space="preserve">简介</w:t></w:r></w:hyperlink></w:p>`,
+		`<w:pStyle w:val="TOC2"/></w:pPr><w:hyperlink w:anchor="背景__目标" w:history="1"><w:r><w:t xml:space="preserve">背景 &amp; 目标</w:t></w:r></w:hyperlink><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("目录缺少%s，实际XML为:\n%s", want, xml)
+		}
+	}
+	if strings.Contains(xml, `<w:t xml:space="preserve">细节</w:t>`) {
+		t.Error("目录不应包含三级以下的标题")
+	}
+	if !strings.Contains(g.settingsXML(), `<w:updateFields w:val="true"/>`) {
+		t.Error("包含目录时应要求Word打开时更新域")
+	}
+
+	t.Run("通过选项插入", func(t *testing.T) {
+		xml := newGenerator(Options{TOC: true}).documentXML(models.Document{Blocks: []models.Block{heading(1, "简介")}})
+		if !strings.Contains(xml, `<w:body><w:p><w:pPr><w:pStyle w:val="TOC1"/>`) {
+			t.Errorf("目录应插入在正文开头，实际XML为:\n%s", xml)
+		}
+		if strings.Contains(newGenerator(Options{}).settingsXML(), "updateFields") {
+			t.Error("没有域时不需要更新域")
+		}
+	})
+}
+
+func TestComplexFieldXML(t *testing.T) {
+	g := newGenerator(Options{})
+	xml := g.complexFieldXML(`TOC \o "1-3"`, []fieldParagraph{{style: "TOC1"}})
+	want := `<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:fldChar w:fldCharType="begin"/></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> TOC \o "1-3" </w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`
+	if xml != want || !g.hasFields {
+		t.Errorf("只有一个段落的域错误:\n%s", xml)
+	}
+	xml = g.complexFieldXML("TOC", []fieldParagraph{{style: "TOC1", content: "<w:r/>"}, {style: "TOC2"}, {style: "TOC2", content: "<w:r/>"}})
+	if strings.Count(xml, "<w:p>") != 3 || !strings.HasPrefix(xml, `<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:fldChar w:fldCharType="begin"/>`) ||
+		!strings.HasSuffix(xml, `<w:r/><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`) || strings.Count(xml, "<w:fldChar ") != 3 {
+		t.Errorf("跨段落的域错误:\n%s", xml)
+	}
+}
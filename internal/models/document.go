@@ -2,7 +2,9 @@ package models
 
 // Document 表示一个文档对象，包含多个块元素
 type Document struct {
-	Blocks []Block
+	Blocks    []Block
+	Footnotes []Footnote // 脚注定义，按定义顺序排列
+	Metadata  Metadata   // 文档元数据
 }
 
 // Block 是文档中的块级元素接口
@@ -12,8 +14,9 @@ type Block interface {
 
 // Header 表示标题元素
 type Header struct {
-	Level int    // 标题级别
-	Text  string // 标题内容
+	Level   int        // 标题级别，1到6
+	Inlines []Inline   // 标题内容
+	Attr    Attributes // 标题属性，如 {#id} 指定锚点，{-} 或 {.unnumbered} 不参与编号
 }
 
 // Type 返回块类型
@@ -74,3 +77,288 @@ func (m Math) Type() string {
 	}
 	return "mathinline"
 }
+
+// Image 表示图片
+type Image struct {
+	Src   string     // 图片路径
+	Alt   string     // 替代文本
+	Title string     // 图片标题
+	Attr  Attributes // 图片属性，如 {width=50%}
+}
+
+// InlineType 返回内联元素类型
+func (i Image) InlineType() string {
+	return "image"
+}
+
+// Attributes 表示 {#id .class key=value} 形式的属性
+type Attributes struct {
+	ID      string            // 标识符
+	Classes []string          // 类名
+	Values  map[string]string // 键值对
+}
+
+// Get 返回指定键的属性值
+func (a Attributes) Get(key string) string {
+	return a.Values[key]
+}
+
+// HasClass 判断是否包含指定类名
+func (a Attributes) HasClass(class string) bool {
+	for _, c := range a.Classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Figure 表示带编号题注的图
+type Figure struct {
+	Image   Image    // 图片，Image.Attr.ID 为图的标识符，如 fig:arch
+	Caption []Inline // 题注内容
+}
+
+// Type 返回块类型
+func (f Figure) Type() string {
+	return "figure"
+}
+
+// ListOfFigures 表示自动生成的图目录
+type ListOfFigures struct{}
+
+// Type 返回块类型
+func (l ListOfFigures) Type() string {
+	return "listoffigures"
+}
+
+// TableOfContents 表示目录，由 [TOC] 或 [[_TOC_]] 标记插入
+type TableOfContents struct{}
+
+// Type 返回块类型
+func (t TableOfContents) Type() string {
+	return "tableofcontents"
+}
+
+// Reference 表示对图等带编号元素的交叉引用，如 @fig:arch
+type Reference struct {
+	ID string // 被引用元素的标识符
+}
+
+// InlineType 返回内联元素类型
+func (r Reference) InlineType() string {
+	return "reference"
+}
+
+// PlainText 返回内联元素的纯文本内容
+func PlainText(inlines []Inline) string {
+	text := ""
+	for _, inline := range inlines {
+		switch i := inline.(type) {
+		case Text:
+			text += i.Content
+		case Bold:
+			text += PlainText(i.Content)
+		case Math:
+			text += i.LaTeX
+		case Image:
+			text += i.Alt
+		case Link:
+			text += PlainText(i.Content)
+		case LineBreak:
+			text += " "
+		case Code:
+			text += i.Content
+		case Subscript:
+			text += PlainText(i.Content)
+		case Superscript:
+			text += PlainText(i.Content)
+		case Comment:
+			text += PlainText(i.Content)
+		}
+	}
+	return text
+}
+
+// Link 表示超链接
+type Link struct {
+	Content []Inline // 链接文本
+	URL     string   // 链接地址，以 # 开头时指向文档内的标题
+	Title   string   // 链接提示文字
+}
+
+// InlineType 返回内联元素类型
+func (l Link) InlineType() string {
+	return "link"
+}
+
+// BlockQuote 表示引用块，可以包含任意块元素，包括嵌套的引用块
+type BlockQuote struct {
+	Blocks []Block // 引用块中的块元素
+}
+
+// Type 返回块类型
+func (q BlockQuote) Type() string {
+	return "blockquote"
+}
+
+// Callout 表示提示块，如 > [!NOTE] 或 ::: tip
+type Callout struct {
+	Kind   string   // 提示类型，小写，如 note、tip、warning
+	Title  []Inline // 自定义标题，为空时使用类型的默认标题
+	Blocks []Block  // 提示块中的块元素
+}
+
+// Type 返回块类型
+func (c Callout) Type() string {
+	return "callout"
+}
+
+// Footnote 表示脚注的定义
+type Footnote struct {
+	ID     string  // 脚注标签，如 [^1] 中的 1
+	Blocks []Block // 脚注内容
+}
+
+// FootnoteReference 表示正文中对脚注的引用
+type FootnoteReference struct {
+	ID string // 被引用的脚注标签
+}
+
+// InlineType 返回内联元素类型
+func (f FootnoteReference) InlineType() string {
+	return "footnotereference"
+}
+
+// HorizontalRule 表示分隔线
+type HorizontalRule struct{}
+
+// Type 返回块类型
+func (h HorizontalRule) Type() string {
+	return "horizontalrule"
+}
+
+// PageBreak 表示分页符
+type PageBreak struct{}
+
+// Type 返回块类型
+func (p PageBreak) Type() string {
+	return "pagebreak"
+}
+
+// SectionBreak 表示分节符，新节从新页开始
+type SectionBreak struct {
+	Orientation string // 新节的纸张方向：portrait 或 landscape，为空时与上一节相同
+}
+
+// Type 返回块类型
+func (s SectionBreak) Type() string {
+	return "sectionbreak"
+}
+
+// Columns 表示分栏排版的内容，前后以连续分节符与其他内容分开
+type Columns struct {
+	Count  int     // 栏数
+	Gap    string  // 栏间距，如 8mm，为空时使用默认间距
+	Blocks []Block // 分栏排版的块元素
+}
+
+// Type 返回块类型
+func (c Columns) Type() string {
+	return "columns"
+}
+
+// ColumnBreak 表示分栏符，之后的内容从下一栏开始
+type ColumnBreak struct{}
+
+// Type 返回块类型
+func (c ColumnBreak) Type() string {
+	return "columnbreak"
+}
+
+// Comment 表示批注，来自CriticMarkup的 {>>批注<<} 或 {==被批注的文字==}{>>批注<<}
+type Comment struct {
+	Content []Inline // 被批注的文字，为空时批注位于当前位置
+	Note    []Inline // 批注内容，为空时只突出显示被批注的文字
+}
+
+// InlineType 返回内联元素类型
+func (c Comment) InlineType() string {
+	return "comment"
+}
+
+// LineBreak 表示段落内的硬换行
+type LineBreak struct{}
+
+// InlineType 返回内联元素类型
+func (l LineBreak) InlineType() string {
+	return "linebreak"
+}
+
+// Code 表示行内代码
+type Code struct {
+	Content string // 代码文本，不解析其中的Markdown语法
+}
+
+// InlineType 返回内联元素类型
+func (c Code) InlineType() string {
+	return "code"
+}
+
+// DefinitionList 表示定义列表，如术语表
+type DefinitionList struct {
+	Items []DefinitionItem
+}
+
+// Type 返回块类型
+func (d DefinitionList) Type() string {
+	return "definitionlist"
+}
+
+// DefinitionItem 表示定义列表中的一个术语及其定义
+type DefinitionItem struct {
+	Term        []Inline  // 术语
+	Definitions [][]Block // 定义，一个术语可以有多个定义
+}
+
+// RawBlock 表示原样输出的块级内容，如 ```{=openxml} 代码块
+type RawBlock struct {
+	Format  string // 内容格式，如 openxml
+	Content string
+}
+
+// Type 返回块类型
+func (r RawBlock) Type() string {
+	return "rawblock"
+}
+
+// RawInline 表示原样输出的行内内容，如 `...`{=openxml}
+type RawInline struct {
+	Format  string // 内容格式，如 openxml
+	Content string
+}
+
+// InlineType 返回内联元素类型
+func (r RawInline) InlineType() string {
+	return "rawinline"
+}
+
+// Subscript 表示下标文本
+type Subscript struct {
+	Content []Inline
+}
+
+// InlineType 返回内联元素类型
+func (s Subscript) InlineType() string {
+	return "subscript"
+}
+
+// Superscript 表示上标文本
+type Superscript struct {
+	Content []Inline
+}
+
+// InlineType 返回内联元素类型
+func (s Superscript) InlineType() string {
+	return "superscript"
+}
@@ -8,7 +8,7 @@ func TestDocumentStructure(t *testing.T) {
 	// 创建一个包含各种元素的文档
 	doc := Document{
 		Blocks: []Block{
-			Header{Level: 1, Text: "测试标题"},
+			Header{Level: 1, Inlines: []Inline{Text{Content: "测试标题"}}},
 			Paragraph{
 				Inlines: []Inline{
 					Text{Content: "这是普通文本"},
@@ -30,8 +30,8 @@ func TestDocumentStructure(t *testing.T) {
 		if header.Level != 1 {
 			t.Errorf("期望标题级别为1，实际为%d", header.Level)
 		}
-		if header.Text != "测试标题" {
-			t.Errorf("期望标题文本为'测试标题'，实际为'%s'", header.Text)
+		if PlainText(header.Inlines) != "测试标题" {
+			t.Errorf("期望标题文本为'测试标题'，实际为'%s'", PlainText(header.Inlines))
 		}
 		if header.Type() != "header" {
 			t.Errorf("期望标题类型为'header'，实际为'%s'", header.Type())
@@ -113,3 +113,15 @@ func TestDocumentStructure(t *testing.T) {
 		t.Error("第三个块元素应该是Math类型")
 	}
 }
+
+func TestPlainText(t *testing.T) {
+	inlines := []Inline{
+		Text{Content: "质能方程"},
+		Bold{Content: []Inline{Text{Content: "公式"}}},
+		Math{LaTeX: "E=mc^2"},
+		Image{Alt: "图"},
+	}
+	if got := PlainText(inlines); got != "质能方程公式E=mc^2图" {
+		t.Errorf("期望纯文本为'质能方程公式E=mc^2图'，实际为'%s'", got)
+	}
+}
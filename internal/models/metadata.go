@@ -0,0 +1,13 @@
+package models
+
+// Metadata 表示文档的元数据，通常来自Markdown开头的YAML块
+type Metadata struct {
+	Title    string            // 标题
+	Subtitle string            // 副标题
+	Authors  []string          // 作者，可以有多位
+	Date     string            // 日期，保留原文
+	Abstract string            // 摘要，段落之间以空行分隔
+	Keywords []string          // 关键词
+	Lang     string            // 文档语言，如 zh-CN
+	Extra    map[string]string // 其他字段，列表以逗号连接
+}
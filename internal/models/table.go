@@ -0,0 +1,43 @@
+package models
+
+// Table 表示表格元素
+type Table struct {
+	Columns []TableColumn // 列定义
+	Head    []TableRow    // 表头行
+	Rows    []TableRow    // 表体行
+}
+
+// Type 返回块类型
+func (t Table) Type() string {
+	return "table"
+}
+
+// TableColumn 表示表格列的定义
+type TableColumn struct {
+	Align string  // 对齐方式: left、center、right，空字符串表示默认
+	Width float64 // 相对宽度(0~1)，为0时表示自动分配
+}
+
+// TableRow 表示表格中的一行
+type TableRow struct {
+	Cells []TableCell // 行内单元格，被上方单元格跨行占据的位置不出现在此
+}
+
+// TableCell 表示表格单元格
+type TableCell struct {
+	Blocks  []Block // 单元格内的块元素
+	ColSpan int     // 跨列数，0或1表示不跨列
+	RowSpan int     // 跨行数，0或1表示不跨行
+}
+
+// Span 返回单元格实际的跨列数和跨行数
+func (c TableCell) Span() (cols, rows int) {
+	cols, rows = c.ColSpan, c.RowSpan
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return cols, rows
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// parseAttributes 解析以 "{" 开头的属性块，如 {#id .class key=value}，返回属性和消耗的字节数。
+// 单独的 - 是 .unnumbered 的简写
+func parseAttributes(text string) (models.Attributes, int, bool) {
+	var attr models.Attributes
+	if !strings.HasPrefix(text, "{") {
+		return attr, 0, false
+	}
+	end := strings.Index(text, "}")
+	if end == -1 {
+		return attr, 0, false
+	}
+	for _, field := range splitAttributeFields(text[1:end]) {
+		switch {
+		case strings.HasPrefix(field, "#") && len(field) > 1:
+			attr.ID = field[1:]
+		case field == "-":
+			attr.Classes = append(attr.Classes, "unnumbered")
+		case strings.HasPrefix(field, ".") && len(field) > 1:
+			attr.Classes = append(attr.Classes, field[1:])
+		case strings.Contains(field, "="):
+			kv := strings.SplitN(field, "=", 2)
+			if attr.Values == nil {
+				attr.Values = map[string]string{}
+			}
+			attr.Values[kv[0]] = strings.Trim(kv[1], `"`)
+		default:
+			return models.Attributes{}, 0, false
+		}
+	}
+	return attr, end + 1, true
+}
+
+// splitAttributeFields 按空白拆分属性字段，引号内的空白不拆分
+func splitAttributeFields(s string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			field.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// trailingAttributes 拆分文本末尾的属性块，如标题 "安装 {#install -}"，没有属性块时返回原文本
+func trailingAttributes(text string) (string, models.Attributes) {
+	if !strings.HasSuffix(text, "}") {
+		return text, models.Attributes{}
+	}
+	open := strings.LastIndex(text, "{")
+	if open == -1 {
+		return text, models.Attributes{}
+	}
+	attr, n, ok := parseAttributes(text[open:])
+	if !ok || open+n != len(text) {
+		return text, models.Attributes{}
+	}
+	return strings.TrimSpace(text[:open]), attr
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseAttributes(t *testing.T) {
+	attr, n, ok := parseAttributes(`{#fig:arch .wide width=50% title="系统 架构"} 后续文本`)
+	if !ok {
+		t.Fatal("属性块应解析成功")
+	}
+	if n != len(`{#fig:arch .wide width=50% title="系统 架构"}`) {
+		t.Errorf("消耗的字节数错误，实际为%d", n)
+	}
+	if attr.ID != "fig:arch" {
+		t.Errorf("标识符解析错误，实际为'%s'", attr.ID)
+	}
+	if !reflect.DeepEqual(attr.Classes, []string{"wide"}) {
+		t.Errorf("类名解析错误，实际为%v", attr.Classes)
+	}
+	if attr.Get("width") != "50%" || attr.Get("title") != "系统 架构" {
+		t.Errorf("键值对解析错误，实际为%v", attr.Values)
+	}
+
+	if _, _, ok := parseAttributes("{不是属性}"); ok {
+		t.Error("无效的属性块不应解析成功")
+	}
+}
+
+func TestTrailingAttributes(t *testing.T) {
+	testCases := []struct {
+		input, text, id string
+		unnumbered      bool
+	}{
+		{"安装 {#install -}", "安装", "install", true},
+		{"附录 {.unnumbered}", "附录", "", true},
+		{"集合 {a, b}", "集合 {a, b}", "", false},
+		{"普通标题", "普通标题", "", false},
+	}
+	for _, tc := range testCases {
+		text, attr := trailingAttributes(tc.input)
+		if text != tc.text || attr.ID != tc.id || attr.HasClass("unnumbered") != tc.unnumbered {
+			t.Errorf("trailingAttributes(%q) 返回 %q %#v", tc.input, text, attr)
+		}
+	}
+
+	doc := ParseMarkdown("# 简介 {#intro}\n\n致谢 {-}\n----")
+	for i, id := range []string{"intro", ""} {
+		if h, ok := doc.Blocks[i].(models.Header); !ok || h.Attr.ID != id || (i == 1) != h.Attr.HasClass("unnumbered") {
+			t.Errorf("第%d个标题的属性解析错误，实际为%#v", i+1, doc.Blocks[i])
+		}
+	}
+}
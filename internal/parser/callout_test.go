@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseCallouts(t *testing.T) {
+	t.Run("GitHub风格提示", func(t *testing.T) {
+		doc := ParseMarkdown("> [!WARNING]\n> 请先备份数据。")
+		callout, ok := doc.Blocks[0].(models.Callout)
+		if !ok {
+			t.Fatalf("应解析为Callout类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if callout.Kind != "warning" || len(callout.Title) != 0 || len(callout.Blocks) != 1 {
+			t.Errorf("提示块解析错误，实际为%+v", callout)
+		}
+	})
+
+	t.Run("Obsidian风格提示带标题", func(t *testing.T) {
+		doc := ParseMarkdown("> [!tip]- 使用**快捷键**\n> 按 F9 更新域。")
+		callout := doc.Blocks[0].(models.Callout)
+		if callout.Kind != "tip" || models.PlainText(callout.Title) != "使用快捷键" {
+			t.Errorf("提示块标题解析错误，实际为%+v", callout)
+		}
+	})
+
+	t.Run("围栏提示块支持嵌套", func(t *testing.T) {
+		md := "::: tip 小技巧\n外层内容\n\n::: {.caution}\n内层内容\n:::\n:::\n\n之后的段落"
+		doc := ParseMarkdown(md)
+		if len(doc.Blocks) != 2 {
+			t.Fatalf("期望解析出2个块元素，实际为%d", len(doc.Blocks))
+		}
+		outer, ok := doc.Blocks[0].(models.Callout)
+		if !ok || outer.Kind != "tip" || models.PlainText(outer.Title) != "小技巧" {
+			t.Fatalf("外层提示块解析错误，实际为%#v", doc.Blocks[0])
+		}
+		if len(outer.Blocks) != 2 {
+			t.Fatalf("外层提示块应包含段落和嵌套提示块，实际为%#v", outer.Blocks)
+		}
+		if inner, ok := outer.Blocks[1].(models.Callout); !ok || inner.Kind != "caution" {
+			t.Errorf("嵌套提示块解析错误，实际为%#v", outer.Blocks[1])
+		}
+	})
+
+	t.Run("未闭合的围栏保留为文本", func(t *testing.T) {
+		doc := ParseMarkdown("::: note\n没有结束标记")
+		if _, ok := doc.Blocks[0].(models.Paragraph); !ok {
+			t.Errorf("未闭合的围栏应作为段落，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+	})
+}
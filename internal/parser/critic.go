@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// parseComment 解析CriticMarkup的批注 {>>批注<<} 和突出显示 {==文字==}，突出显示之后紧跟的批注针对这段文字。
+// 返回批注和消耗的字节数，缺少结束标记时返回false
+func (p *markdownParser) parseComment(text string) (models.Comment, int, bool) {
+	var comment models.Comment
+	n := 0
+	if strings.HasPrefix(text, "{==") {
+		end := strings.Index(text[3:], "==}")
+		if end == -1 {
+			return comment, 0, false
+		}
+		comment.Content = p.parseInlines(text[3 : 3+end])
+		n = 3 + end + 3
+	}
+	if strings.HasPrefix(text[n:], "{>>") {
+		end := strings.Index(text[n+3:], "<<}")
+		if end == -1 {
+			// 突出显示之后不完整的批注按普通文本处理
+			return comment, n, n > 0
+		}
+		comment.Note = p.parseInlines(strings.TrimSpace(text[n+3 : n+3+end]))
+		n += 3 + end + 3
+	}
+	return comment, n, true
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseComments(t *testing.T) {
+	t.Run("突出显示和批注", func(t *testing.T) {
+		doc := ParseMarkdown("这句话{==表述不清==}{>>建议改写<<}，另见{>> 补充**数据** <<}。")
+		p := doc.Blocks[0].(models.Paragraph)
+		if len(p.Inlines) != 5 {
+			t.Fatalf("内联元素解析错误: %#v", p.Inlines)
+		}
+		c, ok := p.Inlines[1].(models.Comment)
+		if !ok || models.PlainText(c.Content) != "表述不清" || models.PlainText(c.Note) != "建议改写" {
+			t.Errorf("突出显示之后的批注应针对这段文字: %#v", p.Inlines[1])
+		}
+		c, ok = p.Inlines[3].(models.Comment)
+		if !ok || len(c.Content) != 0 || len(c.Note) != 2 {
+			t.Errorf("单独的批注解析错误: %#v", p.Inlines[3])
+		}
+		if _, ok := c.Note[1].(models.Bold); !ok {
+			t.Error("批注内容应解析内联元素")
+		}
+		if text := models.PlainText(p.Inlines); text != "这句话表述不清，另见。" {
+			t.Errorf("批注内容不应计入正文: %s", text)
+		}
+	})
+
+	t.Run("只有突出显示", func(t *testing.T) {
+		doc := ParseMarkdown("{==重点==} 内容")
+		c, ok := doc.Blocks[0].(models.Paragraph).Inlines[0].(models.Comment)
+		if !ok || models.PlainText(c.Content) != "重点" || c.Note != nil {
+			t.Errorf("突出显示解析错误: %#v", doc.Blocks[0])
+		}
+	})
+
+	t.Run("不完整的标记", func(t *testing.T) {
+		for _, md := range []string{"{==未结束", "{>>未结束", "集合{a, b}"} {
+			p := ParseMarkdown(md).Blocks[0].(models.Paragraph)
+			if text := models.PlainText(p.Inlines); text != md {
+				t.Errorf("%s 应保留为文本，实际为 %s", md, text)
+			}
+		}
+		p := ParseMarkdown("{==重点==}{>>未结束").Blocks[0].(models.Paragraph)
+		if _, ok := p.Inlines[0].(models.Comment); !ok || models.PlainText(p.Inlines) != "重点{>>未结束" {
+			t.Errorf("未结束的批注应保留为文本: %#v", p.Inlines)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// parseDefinitionList 解析从第start行开始的定义列表：术语单独一行，之后（可隔一个空行）是以 : 或 ~ 开头的定义，
+// 定义的后续行需要缩进。start处不是定义列表时返回false
+func (p *markdownParser) parseDefinitionList(lines []string, start int) (models.Block, int, bool) {
+	var list models.DefinitionList
+	i := start
+	for {
+		first := definitionStart(lines, i)
+		if first == -1 {
+			break
+		}
+		item := models.DefinitionItem{Term: p.parseInlines(strings.TrimSpace(lines[i]))}
+		i = first
+		for i < len(lines) {
+			content, ok := definitionMarker(lines[i])
+			if !ok {
+				break
+			}
+			body := []string{content}
+			i++
+			for i < len(lines) {
+				if strings.TrimSpace(lines[i]) == "" {
+					// 空行之后仍缩进的行属于同一定义
+					j := i
+					for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+						j++
+					}
+					if j == len(lines) || !isDefinitionContinuation(lines[j]) {
+						break
+					}
+					for ; i < j; i++ {
+						body = append(body, "")
+					}
+					continue
+				}
+				if !isDefinitionContinuation(lines[i]) {
+					break
+				}
+				body = append(body, trimIndent(lines[i], 4))
+				i++
+			}
+			item.Definitions = append(item.Definitions, p.parseBlocks(body))
+			// 同一术语的定义之间可以有空行
+			if next := skipBlank(lines, i); next < len(lines) {
+				if _, ok := definitionMarker(lines[next]); ok {
+					i = next
+				}
+			}
+		}
+		list.Items = append(list.Items, item)
+		i = skipBlank(lines, i)
+	}
+	if len(list.Items) == 0 {
+		return nil, start, false
+	}
+	return list, i, true
+}
+
+// definitionStart 判断第i行是否为术语，是时返回其第一个定义所在的行号，否则返回-1
+func definitionStart(lines []string, i int) int {
+	if i >= len(lines) || strings.TrimSpace(lines[i]) == "" || isDefinitionContinuation(lines[i]) {
+		return -1
+	}
+	if _, ok := definitionMarker(lines[i]); ok {
+		return -1
+	}
+	next := i + 1
+	if next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+		next++
+	}
+	if next < len(lines) {
+		if _, ok := definitionMarker(lines[next]); ok {
+			return next
+		}
+	}
+	return -1
+}
+
+// definitionMarker 判断行是否以定义标记 : 或 ~ 开头，返回标记之后的内容
+func definitionMarker(line string) (string, bool) {
+	rest := strings.TrimLeft(line, " ")
+	if len(line)-len(rest) > 3 || len(rest) < 2 || (rest[0] != ':' && rest[0] != '~') || (rest[1] != ' ' && rest[1] != '\t') {
+		return "", false
+	}
+	return strings.TrimLeft(rest[1:], " \t"), true
+}
+
+// isDefinitionContinuation 判断行是否缩进至少两个空格或一个制表符，属于前一个定义
+func isDefinitionContinuation(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+// trimIndent 去掉最多n个空格的缩进，开头的制表符视为一级缩进
+func trimIndent(line string, n int) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// skipBlank 返回从第i行起第一个非空行的行号
+func skipBlank(lines []string, i int) int {
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	return i
+}
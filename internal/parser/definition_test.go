@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseDefinitionList(t *testing.T) {
+	md := "**API**\n: 应用程序接口\n\n    第二段\n: 另一个定义\n\nSDK\n\n~   软件开发工具包\n    延续行\n\n第三项\n: 定义\n\n结尾段落"
+	doc := ParseMarkdown(md)
+
+	if len(doc.Blocks) != 2 {
+		t.Fatalf("期望解析出定义列表和段落，实际为%#v", doc.Blocks)
+	}
+	list, ok := doc.Blocks[0].(models.DefinitionList)
+	if !ok || len(list.Items) != 3 {
+		t.Fatalf("定义列表解析错误，实际为%#v", doc.Blocks[0])
+	}
+	api := list.Items[0]
+	if _, ok := api.Term[0].(models.Bold); !ok {
+		t.Errorf("术语应解析内联元素，实际为%#v", api.Term)
+	}
+	if len(api.Definitions) != 2 || len(api.Definitions[0]) != 2 {
+		t.Errorf("API应有两个定义，第一个定义包含两段，实际为%#v", api.Definitions)
+	}
+	sdk := list.Items[1]
+	if models.PlainText(sdk.Term) != "SDK" || len(sdk.Definitions) != 1 {
+		t.Fatalf("术语与定义之间可以有空行，实际为%#v", sdk)
+	}
+	if text := models.PlainText(sdk.Definitions[0][0].(models.Paragraph).Inlines); text != "软件开发工具包 延续行" {
+		t.Errorf("定义的缩进延续行应并入段落，实际为'%s'", text)
+	}
+	if models.PlainText(list.Items[2].Term) != "第三项" {
+		t.Errorf("第三个术语解析错误，实际为%#v", list.Items[2])
+	}
+	if p, ok := doc.Blocks[1].(models.Paragraph); !ok || models.PlainText(p.Inlines) != "结尾段落" {
+		t.Errorf("定义列表之后的段落解析错误，实际为%#v", doc.Blocks[1])
+	}
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// footnoteDefinitionPattern 匹配脚注定义的首行，如 [^1]: 脚注内容
+var footnoteDefinitionPattern = regexp.MustCompile(`^ {0,3}\[\^([^\]\s]+)\]:\s?(.*)$`)
+
+// collectFootnotes 收集脚注定义并从文本行中移除。定义之后缩进的行属于同一脚注，
+// 紧跟的未缩进行作为段落的延续，代码块中的内容不受影响
+func (p *markdownParser) collectFootnotes(lines []string) []string {
+	var result []string
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		m := footnoteDefinitionPattern.FindStringSubmatch(line)
+		if inFence || m == nil {
+			result = append(result, line)
+			continue
+		}
+		body := []string{m[2]}
+		for i+1 < len(lines) {
+			next := lines[i+1]
+			if strings.TrimSpace(next) == "" {
+				// 空行之后只有缩进的行仍属于脚注
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j == len(lines) || !isIndented(lines[j]) {
+					break
+				}
+				for ; i+1 < j; i++ {
+					body = append(body, "")
+				}
+				continue
+			}
+			if isIndented(next) {
+				body = append(body, unindent(next))
+			} else if lazy := strings.TrimSpace(next); strings.TrimSpace(body[len(body)-1]) != "" &&
+				!footnoteDefinitionPattern.MatchString(next) && !isHeading(next) && !strings.HasPrefix(lazy, "```") {
+				body = append(body, next)
+			} else {
+				break
+			}
+			i++
+		}
+		if _, ok := p.footnoteLines[m[1]]; !ok {
+			p.footnoteLines[m[1]] = body
+			p.footnotes = append(p.footnotes, models.Footnote{ID: m[1]})
+		}
+	}
+	return result
+}
+
+// parseFootnotes 解析已收集的脚注定义的内容，需在收集链接引用定义之后调用
+func (p *markdownParser) parseFootnotes() {
+	for i := 0; i < len(p.footnotes); i++ {
+		if lines, ok := p.footnoteLines[p.footnotes[i].ID]; ok {
+			p.footnotes[i].Blocks = p.parseBlocks(lines)
+		}
+	}
+}
+
+// parseFootnoteReference 解析 [^label] 形式的脚注引用，未定义的标签不构成引用
+func (p *markdownParser) parseFootnoteReference(text string) (models.FootnoteReference, int, bool) {
+	end := strings.IndexByte(text, ']')
+	if end == -1 {
+		return models.FootnoteReference{}, 0, false
+	}
+	label := text[2:end]
+	if _, ok := p.footnoteLines[label]; !ok {
+		return models.FootnoteReference{}, 0, false
+	}
+	return models.FootnoteReference{ID: label}, end + 1, true
+}
+
+// parseInlineNote 解析 ^[脚注内容] 形式的行内脚注，为其生成不会与脚注标签冲突的标识符
+func (p *markdownParser) parseInlineNote(text string) (models.FootnoteReference, int, bool) {
+	end := matchingBracket(text, 1, '[', ']')
+	if end == -1 {
+		return models.FootnoteReference{}, 0, false
+	}
+	p.inlineNotes++
+	// 脚注标签不含空白，带空格的标识符不会与之冲突
+	id := fmt.Sprintf("inline %d", p.inlineNotes)
+	p.footnotes = append(p.footnotes, models.Footnote{
+		ID:     id,
+		Blocks: []models.Block{p.parseParagraph(text[2:end])},
+	})
+	return models.FootnoteReference{ID: id}, end + 1, true
+}
+
+// isIndented 判断行是否缩进了至少4个空格或一个制表符
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// unindent 去掉一级缩进
+func unindent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	return strings.TrimPrefix(line, "    ")
+}
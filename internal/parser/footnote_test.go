@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseFootnotes(t *testing.T) {
+	t.Run("引用和定义", func(t *testing.T) {
+		md := "正文[^1]和[^note]，未定义[^x]。\n\n[^1]: 第一条脚注\n    继续缩进\n\n    第二段\n\n[^note]: 带**粗体**的脚注\n\n结尾段落"
+		doc := ParseMarkdown(md)
+		if len(doc.Blocks) != 2 {
+			t.Fatalf("脚注定义应从正文中移除，实际块数为%d", len(doc.Blocks))
+		}
+		p := doc.Blocks[0].(models.Paragraph)
+		if ref, ok := p.Inlines[1].(models.FootnoteReference); !ok || ref.ID != "1" {
+			t.Errorf("第二个内联元素应为脚注引用，实际为%#v", p.Inlines[1])
+		}
+		if last := p.Inlines[len(p.Inlines)-1].(models.Text); last.Content != "，未定义[^x]。" {
+			t.Errorf("未定义的脚注应保留为文本，实际为'%s'", last.Content)
+		}
+		if len(doc.Footnotes) != 2 || doc.Footnotes[0].ID != "1" || doc.Footnotes[1].ID != "note" {
+			t.Fatalf("脚注定义解析错误: %#v", doc.Footnotes)
+		}
+		if n := len(doc.Footnotes[0].Blocks); n != 2 {
+			t.Errorf("缩进的内容应属于同一脚注，期望2段，实际为%d", n)
+		}
+		first := doc.Footnotes[0].Blocks[0].(models.Paragraph)
+		if text := models.PlainText(first.Inlines); text != "第一条脚注 继续缩进" {
+			t.Errorf("脚注第一段为'%s'", text)
+		}
+	})
+
+	t.Run("行内脚注", func(t *testing.T) {
+		doc := ParseMarkdown("公式^[见 $E=mc^2$ 的推导]成立，x^2 保持原样")
+		p := doc.Blocks[0].(models.Paragraph)
+		ref, ok := p.Inlines[1].(models.FootnoteReference)
+		if !ok || len(doc.Footnotes) != 1 || doc.Footnotes[0].ID != ref.ID {
+			t.Fatalf("行内脚注解析错误: %#v %#v", p.Inlines, doc.Footnotes)
+		}
+		note := doc.Footnotes[0].Blocks[0].(models.Paragraph)
+		if _, ok := note.Inlines[1].(models.Math); !ok {
+			t.Error("行内脚注的内容应解析内联元素")
+		}
+		if text := p.Inlines[2].(models.Text).Content; text != "成立，x^2 保持原样" {
+			t.Errorf("普通的^应保留为文本，实际为'%s'", text)
+		}
+	})
+
+	t.Run("代码块中的定义不被收集", func(t *testing.T) {
+		doc := ParseMarkdown("```math\n[^1]: x\n```")
+		if len(doc.Footnotes) != 0 {
+			t.Error("代码块中的内容不应作为脚注定义")
+		}
+	})
+}
@@ -0,0 +1,390 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// 支持的HTML子集：
+//   - 行内：<br>、<sub>、<sup>、<kbd>和<code>（作为行内代码）、<b>/<strong>、<a href>、<img src alt width height>，注释被忽略
+//   - 块级：<details>/<summary>（作为提示块）、<table>/<thead>/<tbody>/<tr>/<th>/<td colspan rowspan align>
+//
+// 其他标签被丢弃并输出提示，标签中的文本按普通Markdown处理
+
+// htmlTag 表示一个HTML标签或注释
+type htmlTag struct {
+	name        string            // 小写的标签名，注释为 "!--"
+	attrs       map[string]string // 属性，属性名为小写
+	closing     bool              // 是否为结束标签，如 </b>
+	selfClosing bool              // 是否为自闭合标签，如 <br/>
+}
+
+// parseHTMLTag 解析text开头的HTML标签或注释，返回标签和消耗的字节数。容忍未加引号的属性值和大小写混用
+func parseHTMLTag(text string) (htmlTag, int, bool) {
+	if strings.HasPrefix(text, "<!--") {
+		end := strings.Index(text[4:], "-->")
+		if end == -1 {
+			return htmlTag{}, 0, false
+		}
+		return htmlTag{name: "!--"}, 4 + end + 3, true
+	}
+	if !strings.HasPrefix(text, "<") {
+		return htmlTag{}, 0, false
+	}
+	i := 1
+	tag := htmlTag{attrs: map[string]string{}}
+	if i < len(text) && text[i] == '/' {
+		tag.closing = true
+		i++
+	}
+	start := i
+	for i < len(text) && (isASCIILetter(text[i]) || (i > start && (text[i] >= '0' && text[i] <= '9' || text[i] == '-'))) {
+		i++
+	}
+	if i == start {
+		return htmlTag{}, 0, false
+	}
+	tag.name = strings.ToLower(text[start:i])
+	for i < len(text) {
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		if i >= len(text) {
+			return htmlTag{}, 0, false
+		}
+		switch {
+		case text[i] == '>':
+			return tag, i + 1, true
+		case strings.HasPrefix(text[i:], "/>"):
+			tag.selfClosing = true
+			return tag, i + 2, true
+		case tag.closing:
+			return htmlTag{}, 0, false
+		}
+		// 属性名
+		nameStart := i
+		for i < len(text) && !isHTMLSpace(text[i]) && !strings.ContainsRune("=>/\"'<", rune(text[i])) {
+			i++
+		}
+		if i == nameStart {
+			return htmlTag{}, 0, false
+		}
+		name := strings.ToLower(text[nameStart:i])
+		value := ""
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		if i < len(text) && text[i] == '=' {
+			i++
+			for i < len(text) && isHTMLSpace(text[i]) {
+				i++
+			}
+			if i < len(text) && (text[i] == '"' || text[i] == '\'') {
+				end := strings.IndexByte(text[i+1:], text[i])
+				if end == -1 {
+					return htmlTag{}, 0, false
+				}
+				value = text[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(text) && !isHTMLSpace(text[i]) && text[i] != '>' {
+					i++
+				}
+				value = text[valueStart:i]
+			}
+		}
+		tag.attrs[name] = unescapeHTML(value)
+	}
+	return htmlTag{}, 0, false
+}
+
+// htmlVoidElements 没有结束标签的元素
+var htmlVoidElements = map[string]bool{"br": true, "img": true, "hr": true, "wbr": true, "input": true, "meta": true, "source": true}
+
+// findClosingTag 在text中查找与已打开的name标签匹配的结束标签，返回结束标签的起止位置，同名标签可以嵌套
+func findClosingTag(text, name string) (int, int) {
+	depth := 1
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+		tag, n, ok := parseHTMLTag(text[i:])
+		if !ok || tag.name != name || tag.selfClosing {
+			continue
+		}
+		if tag.closing {
+			depth--
+			if depth == 0 {
+				return i, i + n
+			}
+		} else {
+			depth++
+		}
+		i += n - 1
+	}
+	return -1, -1
+}
+
+// parseHTMLInline 解析行内的HTML标签，返回对应的内联元素（可能为空）和消耗的字节数
+func (p *markdownParser) parseHTMLInline(text string) ([]models.Inline, int, bool) {
+	tag, n, ok := parseHTMLTag(text)
+	if !ok {
+		return nil, 0, false
+	}
+	switch {
+	case tag.name == "!--":
+		return nil, n, true
+	case tag.name == "br" && !tag.closing:
+		return []models.Inline{models.LineBreak{}}, n, true
+	case tag.name == "img" && !tag.closing:
+		return []models.Inline{htmlImage(tag)}, n, true
+	case tag.closing || tag.selfClosing || htmlVoidElements[tag.name]:
+		p.warn("忽略HTML标签: %s", text[:n])
+		return nil, n, true
+	}
+	start, end := findClosingTag(text[n:], tag.name)
+	if start == -1 {
+		p.warn("忽略未闭合的HTML标签: %s", text[:n])
+		return nil, n, true
+	}
+	content := p.parseInlines(text[n : n+start])
+	consumed := n + end
+	switch tag.name {
+	case "sub":
+		return []models.Inline{models.Subscript{Content: content}}, consumed, true
+	case "sup":
+		return []models.Inline{models.Superscript{Content: content}}, consumed, true
+	case "kbd", "code":
+		return []models.Inline{models.Code{Content: unescapeHTML(text[n : n+start])}}, consumed, true
+	case "b", "strong":
+		return []models.Inline{models.Bold{Content: content}}, consumed, true
+	case "a":
+		if href := tag.attrs["href"]; href != "" {
+			return []models.Inline{models.Link{Content: content, URL: href, Title: tag.attrs["title"]}}, consumed, true
+		}
+	default:
+		p.warn("忽略不支持的HTML标签 <%s>，保留其中的文本", tag.name)
+	}
+	return content, consumed, true
+}
+
+// htmlImage 将 <img> 标签转换为图片，width和height属性作为图片尺寸
+func htmlImage(tag htmlTag) models.Image {
+	image := models.Image{Src: tag.attrs["src"], Alt: tag.attrs["alt"], Title: tag.attrs["title"]}
+	for _, key := range []string{"width", "height"} {
+		if value, ok := tag.attrs[key]; ok {
+			if image.Attr.Values == nil {
+				image.Attr.Values = map[string]string{}
+			}
+			image.Attr.Values[key] = value
+		}
+	}
+	return image
+}
+
+// isHTMLBlockStart 判断行是否以支持的块级HTML元素开头
+func isHTMLBlockStart(trimmed string) bool {
+	tag, _, ok := parseHTMLTag(trimmed)
+	return ok && !tag.closing && (tag.name == "details" || tag.name == "table")
+}
+
+// parseHTMLBlock 解析从第start行开始的 <details> 或 <table> 元素，返回对应的块元素和之后的行号
+func (p *markdownParser) parseHTMLBlock(lines []string, start int) (models.Block, int, bool) {
+	text := strings.Join(lines[start:], "\n")
+	text = text[strings.IndexByte(text, '<'):]
+	tag, n, ok := parseHTMLTag(text)
+	if !ok {
+		return nil, start, false
+	}
+	closeStart, closeEnd := findClosingTag(text[n:], tag.name)
+	if closeStart == -1 {
+		return nil, start, false
+	}
+	inner := text[n : n+closeStart]
+	// 结束标签所在行之后的内容属于后续的块
+	next := start + strings.Count(text[:n+closeEnd], "\n") + 1
+
+	if tag.name == "details" {
+		callout := models.Callout{Kind: "details"}
+		if s, e := findHTMLElement(inner, "summary"); s != -1 {
+			_, open, _ := parseHTMLTag(inner[s:])
+			closeS, _ := findClosingTag(inner[s+open:], "summary")
+			callout.Title = p.parseInlines(strings.TrimSpace(inner[s+open : s+open+closeS]))
+			inner = inner[:s] + inner[e:]
+		}
+		callout.Blocks = p.parseText(dedentHTML(inner))
+		return callout, next, true
+	}
+	return p.htmlTable(inner), next, true
+}
+
+// findHTMLElement 查找第一个name元素，返回其开始标签的起点和结束标签的终点
+func findHTMLElement(text, name string) (int, int) {
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+		tag, n, ok := parseHTMLTag(text[i:])
+		if ok && tag.name == name && !tag.closing {
+			_, end := findClosingTag(text[i+n:], name)
+			if end == -1 {
+				return -1, -1
+			}
+			return i, i + n + end
+		}
+	}
+	return -1, -1
+}
+
+// htmlTable 将 <table> 的内容转换为表格。<thead> 中的行或开头全部由 <th> 组成的行作为表头
+func (p *markdownParser) htmlTable(inner string) models.Table {
+	var table models.Table
+	var rows []models.TableRow
+	var aligns []string
+	var headRows int
+	inHead, allHeader := false, true
+	for i := 0; i < len(inner); i++ {
+		if inner[i] != '<' {
+			continue
+		}
+		tag, n, ok := parseHTMLTag(inner[i:])
+		if !ok {
+			continue
+		}
+		switch {
+		case tag.name == "thead":
+			inHead = !tag.closing
+		case tag.name == "tr" && !tag.closing:
+			rows = append(rows, models.TableRow{})
+			if inHead {
+				headRows = len(rows)
+			}
+		case (tag.name == "td" || tag.name == "th") && !tag.closing:
+			if len(rows) == 0 {
+				rows = append(rows, models.TableRow{})
+			}
+			end, closeEnd := htmlCellEnd(inner[i+n:], tag.name)
+			cell := models.TableCell{Blocks: p.parseText(dedentHTML(inner[i+n : i+n+end]))}
+			cell.ColSpan, _ = strconv.Atoi(tag.attrs["colspan"])
+			cell.RowSpan, _ = strconv.Atoi(tag.attrs["rowspan"])
+			row := &rows[len(rows)-1]
+			row.Cells = append(row.Cells, cell)
+			if len(rows) == 1 {
+				allHeader = allHeader && tag.name == "th"
+				// 首行单元格的对齐方式决定各列的对齐方式
+				colSpan, _ := cell.Span()
+				for k := 0; k < colSpan; k++ {
+					aligns = append(aligns, htmlAlign(tag))
+				}
+			}
+			n += closeEnd
+		}
+		i += n - 1
+	}
+	if headRows == 0 && allHeader && len(rows) > 1 {
+		headRows = 1
+	}
+	table.Head, table.Rows = rows[:headRows], rows[headRows:]
+
+	table.Columns = make([]models.TableColumn, htmlTableColumns(rows))
+	for c := range table.Columns {
+		if c < len(aligns) {
+			table.Columns[c].Align = aligns[c]
+		}
+	}
+	return table
+}
+
+// htmlCellEnd 查找单元格内容的终点，返回内容的长度和之后应跳过的长度。
+// HTML允许省略 </td> 和 </th>，此时单元格在下一个单元格、行或表格的边界处结束
+func htmlCellEnd(text, name string) (int, int) {
+	if end, closeEnd := findClosingTag(text, name); end != -1 {
+		return end, closeEnd
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+		tag, _, ok := parseHTMLTag(text[i:])
+		if !ok {
+			continue
+		}
+		switch {
+		case (tag.name == "td" || tag.name == "th") && !tag.closing,
+			tag.name == "tr", tag.name == "thead", tag.name == "tbody", tag.name == "tfoot",
+			tag.name == "table" && tag.closing:
+			return i, i
+		}
+	}
+	return len(text), len(text)
+}
+
+// htmlAlign 返回单元格的对齐方式，来自align属性或style中的text-align
+func htmlAlign(tag htmlTag) string {
+	align := strings.ToLower(tag.attrs["align"])
+	if style := strings.ToLower(tag.attrs["style"]); strings.Contains(style, "text-align") {
+		value := style[strings.Index(style, "text-align")+len("text-align"):]
+		value = strings.TrimLeft(value, ": ")
+		if end := strings.IndexAny(value, "; "); end != -1 {
+			value = value[:end]
+		}
+		align = value
+	}
+	switch align {
+	case "left", "center", "right":
+		return align
+	}
+	return ""
+}
+
+// htmlTableColumns 计算表格的列数，考虑跨行单元格占据的位置
+func htmlTableColumns(rows []models.TableRow) int {
+	occupied := map[[2]int]bool{}
+	cols := 0
+	for r, row := range rows {
+		c := 0
+		for _, cell := range row.Cells {
+			for occupied[[2]int{r, c}] {
+				c++
+			}
+			colSpan, rowSpan := cell.Span()
+			for y := r; y < r+rowSpan; y++ {
+				for x := c; x < c+colSpan; x++ {
+					occupied[[2]int{y, x}] = true
+				}
+			}
+			c += colSpan
+			if c > cols {
+				cols = c
+			}
+		}
+	}
+	return cols
+}
+
+// dedentHTML 去掉HTML元素内容各行的公共缩进，避免被当作缩进的延续行
+func dedentHTML(text string) string {
+	lines := strings.Split(strings.Trim(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unescapeHTML 还原常见的HTML字符实体
+func unescapeHTML(text string) string {
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'", "&nbsp;", " ", "&amp;", "&").Replace(text)
+}
+
+// isASCIILetter 判断字符是否为ASCII字母
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isHTMLSpace 判断字符是否为HTML中的空白
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
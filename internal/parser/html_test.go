@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseHTMLTag(t *testing.T) {
+	tag, n, ok := parseHTMLTag(`<IMG src="a.png" Width=120 alt='A &amp; B' hidden/>后文`)
+	if !ok || n != len(`<IMG src="a.png" Width=120 alt='A &amp; B' hidden/>`) {
+		t.Fatalf("标签解析失败: %v %d", ok, n)
+	}
+	expected := map[string]string{"src": "a.png", "width": "120", "alt": "A & B", "hidden": ""}
+	if tag.name != "img" || !tag.selfClosing || !reflect.DeepEqual(tag.attrs, expected) {
+		t.Errorf("标签解析错误: %#v", tag)
+	}
+	for _, text := range []string{"< b>", "table>", "<1>", `<a href="x>`, "</b x>"} {
+		if _, _, ok := parseHTMLTag(text); ok {
+			t.Errorf("%q 不应解析为标签", text)
+		}
+	}
+}
+
+func TestParseHTMLInline(t *testing.T) {
+	doc := ParseMarkdown(`H<sub>2</sub>O 与 x<sup>2</sup>，按 <kbd>Ctrl</kbd>+<kbd>C</kbd>，<b>粗</b><br>` +
+		`<img src="logo.png" width="64" alt="标志"> <span class="x">保留文本</span> <!-- 注释 --> a < b`)
+	inlines := doc.Blocks[0].(models.Paragraph).Inlines
+	expected := []models.Inline{
+		models.Text{Content: "H"},
+		models.Subscript{Content: []models.Inline{models.Text{Content: "2"}}},
+		models.Text{Content: "O 与 x"},
+		models.Superscript{Content: []models.Inline{models.Text{Content: "2"}}},
+		models.Text{Content: "，按 "},
+		models.Code{Content: "Ctrl"},
+		models.Text{Content: "+"},
+		models.Code{Content: "C"},
+		models.Text{Content: "，"},
+		models.Bold{Content: []models.Inline{models.Text{Content: "粗"}}},
+		models.LineBreak{},
+		models.Image{Src: "logo.png", Alt: "标志", Attr: models.Attributes{Values: map[string]string{"width": "64"}}},
+		models.Text{Content: " 保留文本  a < b"},
+	}
+	if !reflect.DeepEqual(inlines, expected) {
+		t.Errorf("行内HTML解析错误:\n期望 %#v\n实际 %#v", expected, inlines)
+	}
+
+	// 忽略的标签作为警告返回
+	_, warnings := ParseMarkdownWithWarnings("<span>文本</span> <u>未闭合 </i>")
+	want := []string{"忽略不支持的HTML标签 <span>，保留其中的文本", "忽略未闭合的HTML标签: <u>", "忽略HTML标签: </i>"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("HTML标签的警告错误，实际为%q", warnings)
+	}
+}
+
+func TestParseHTMLBlocks(t *testing.T) {
+	md := "<details>\n  <summary>展开 **详情**</summary>\n\n  隐藏的内容\n</details>\n\n" +
+		"<table>\n  <tr><th align=\"right\">名称</th><th>说明</th></tr>\n" +
+		"  <tr><td rowspan=\"2\">A</td><td>第一</td></tr>\n  <tr><td>第二</td></tr>\n</table>\n" +
+		"<!--\n多行注释\n-->\n结尾"
+	doc := ParseMarkdown(md)
+	if len(doc.Blocks) != 3 {
+		t.Fatalf("期望解析出提示块、表格和段落，实际为%#v", doc.Blocks)
+	}
+	details, ok := doc.Blocks[0].(models.Callout)
+	if !ok || details.Kind != "details" || models.PlainText(details.Title) != "展开 详情" || len(details.Blocks) != 1 {
+		t.Errorf("<details> 解析错误，实际为%#v", doc.Blocks[0])
+	}
+	table, ok := doc.Blocks[1].(models.Table)
+	if !ok {
+		t.Fatalf("<table> 应解析为表格，实际为%#v", doc.Blocks[1])
+	}
+	if len(table.Columns) != 2 || table.Columns[0].Align != "right" || len(table.Head) != 1 || len(table.Rows) != 2 {
+		t.Errorf("表格结构错误: %#v", table)
+	}
+	if cols, rows := table.Rows[0].Cells[0].Span(); cols != 1 || rows != 2 || len(table.Rows[1].Cells) != 1 {
+		t.Errorf("跨行单元格解析错误: %#v", table.Rows)
+	}
+	if p, ok := doc.Blocks[2].(models.Paragraph); !ok || models.PlainText(p.Inlines) != "结尾" {
+		t.Errorf("注释之后的段落解析错误，实际为%#v", doc.Blocks[2])
+	}
+
+	t.Run("省略结束标签的单元格", func(t *testing.T) {
+		for _, md := range []string{
+			"<table><tr><td>a<td>b</tr></table>",
+			"<table><tr><th>a<th>b</tr></table>",
+			"<table><tr><td>a<td>b</td></tr></table>",
+			"<table><tr><td>a</tr><tr><td>b</table>",
+			"<table><tr><td>a<td>b\n</table>",
+		} {
+			table, ok := ParseMarkdown(md).Blocks[0].(models.Table)
+			if !ok {
+				t.Errorf("%q 应解析为表格", md)
+				continue
+			}
+			var texts []string
+			for _, row := range append(table.Head, table.Rows...) {
+				for _, cell := range row.Cells {
+					texts = append(texts, models.PlainText(cell.Blocks[0].(models.Paragraph).Inlines))
+				}
+			}
+			if !reflect.DeepEqual(texts, []string{"a", "b"}) {
+				t.Errorf("%q 的单元格解析错误: %q", md, texts)
+			}
+		}
+	})
+}
+
+func FuzzParseHTML(f *testing.F) {
+	for _, seed := range []string{
+		"<table><tr><td>a<td>b</tr></table>",
+		"<table>\n<thead><tr><th colspan=2>x</th></tr></thead>\n<tr><td rowspan=\"3\">y<td>z\n</table>",
+		"<details><summary>标题</summary>内容<details>嵌套</details></details>",
+		"<b>粗<sub>下标</b></sub> <a href=x>链接 <img src=a.png width=10%> <br/> <!-- 注释",
+		"<table><tr><td><table><tr><td>内层</td></tr></table></td></tr></table>",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, md string) {
+		ParseMarkdown(md)
+	})
+}
@@ -0,0 +1,333 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// linkDefinition 表示 [label]: url "title" 形式的链接引用定义
+type linkDefinition struct {
+	url   string
+	title string
+}
+
+// linkDefinitionPattern 匹配链接引用定义行，标签以 ^ 开头的是脚注定义
+var linkDefinitionPattern = regexp.MustCompile(`^ {0,3}\[([^\]^][^\]]*)\]:\s*(\S+)(?:\s+["'(](.*)["')])?\s*$`)
+
+// rawAttributePattern 匹配行内代码之后的原样输出标记，如 {=openxml}
+var rawAttributePattern = regexp.MustCompile(`^\{=([A-Za-z][\w-]*)\}`)
+
+// autolinkPattern 匹配 <https://...> 形式的自动链接
+var autolinkPattern = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+
+// emailAutolinkPattern 匹配 <user@example.com> 形式的邮箱自动链接
+var emailAutolinkPattern = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)*)>`)
+
+// collectLinkDefinitions 收集链接引用定义并从文本行中移除，代码块中的内容不受影响
+func (p *markdownParser) collectLinkDefinitions(lines []string) []string {
+	var result []string
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := linkDefinitionPattern.FindStringSubmatch(line); m != nil {
+				label := normalizeLabel(m[1])
+				if _, ok := p.links[label]; !ok {
+					p.links[label] = linkDefinition{url: strings.TrimSuffix(strings.TrimPrefix(m[2], "<"), ">"), title: m[3]}
+				}
+				continue
+			}
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// normalizeLabel 规范化链接标签：忽略大小写，合并连续空白
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// parseParagraph 解析段落文本，识别内联元素
+func (p *markdownParser) parseParagraph(text string) models.Paragraph {
+	return models.Paragraph{Inlines: p.parseInlines(text)}
+}
+
+// parseInlines 将文本解析为内联元素
+func (p *markdownParser) parseInlines(text string) []models.Inline {
+	var inlines []models.Inline
+	for len(text) > 0 {
+		if strings.HasPrefix(text, "`") {
+			code, n, ok := parseCodeSpan(text)
+			if !ok {
+				// 没有匹配的反引号串时整串按文本处理
+				inlines = appendText(inlines, text[:n])
+				text = text[n:]
+				continue
+			}
+			text = text[n:]
+			// 带 {=format} 的行内代码原样输出
+			if m := rawAttributePattern.FindStringSubmatch(text); m != nil {
+				inlines = append(inlines, models.RawInline{Format: m[1], Content: code.Content})
+				text = text[len(m[0]):]
+				continue
+			}
+			inlines = append(inlines, code)
+		} else if strings.HasPrefix(text, "**") {
+			text = text[2:]
+			end := strings.Index(text, "**")
+			if end == -1 {
+				inlines = appendText(inlines, text)
+				break
+			}
+			inlines = append(inlines, models.Bold{Content: []models.Inline{models.Text{Content: text[:end]}}})
+			text = text[end+2:]
+		} else if strings.HasPrefix(text, "$") {
+			text = text[1:]
+			end := strings.Index(text, "$")
+			if end == -1 {
+				inlines = appendText(inlines, text)
+				break
+			}
+			inlines = append(inlines, models.Math{LaTeX: text[:end], Display: false})
+			text = text[end+1:]
+		} else if strings.HasPrefix(text, "![") {
+			image, n, ok := p.parseImage(text)
+			if !ok {
+				inlines = appendText(inlines, "!")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, image)
+			text = text[n:]
+		} else if strings.HasPrefix(text, "@fig:") && !endsWithWordChar(inlines) {
+			n := len("@fig:")
+			for n < len(text) && isLabelChar(text[n]) {
+				n++
+			}
+			// 标识符末尾的标点属于正文
+			for n > len("@fig:") && (text[n-1] == ':' || text[n-1] == '-') {
+				n--
+			}
+			if n == len("@fig:") {
+				inlines = appendText(inlines, "@")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, models.Reference{ID: text[1:n]})
+			text = text[n:]
+		} else if strings.HasPrefix(text, "[^") {
+			ref, n, ok := p.parseFootnoteReference(text)
+			if !ok {
+				inlines = appendText(inlines, "[")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, ref)
+			text = text[n:]
+		} else if strings.HasPrefix(text, "^[") {
+			ref, n, ok := p.parseInlineNote(text)
+			if !ok {
+				inlines = appendText(inlines, "^")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, ref)
+			text = text[n:]
+		} else if strings.HasPrefix(text, "[") {
+			link, n, ok := p.parseLink(text)
+			if !ok {
+				inlines = appendText(inlines, "[")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, link)
+			text = text[n:]
+		} else if strings.HasPrefix(text, "{==") || strings.HasPrefix(text, "{>>") {
+			comment, n, ok := p.parseComment(text)
+			if !ok {
+				inlines = appendText(inlines, "{")
+				text = text[1:]
+				continue
+			}
+			inlines = append(inlines, comment)
+			text = text[n:]
+		} else if strings.HasPrefix(text, "\n") {
+			inlines = append(inlines, models.LineBreak{})
+			text = text[1:]
+		} else if m := autolinkPattern.FindStringSubmatch(text); m != nil {
+			inlines = append(inlines, models.Link{Content: []models.Inline{models.Text{Content: m[1]}}, URL: m[1]})
+			text = text[len(m[0]):]
+		} else if m := emailAutolinkPattern.FindStringSubmatch(text); m != nil {
+			inlines = append(inlines, models.Link{Content: []models.Inline{models.Text{Content: m[1]}}, URL: "mailto:" + m[1]})
+			text = text[len(m[0]):]
+		} else if strings.HasPrefix(text, "<") {
+			html, n, ok := p.parseHTMLInline(text)
+			if !ok {
+				inlines = appendText(inlines, "<")
+				text = text[1:]
+				continue
+			}
+			for _, inline := range html {
+				if t, ok := inline.(models.Text); ok {
+					inlines = appendText(inlines, t.Content)
+				} else {
+					inlines = append(inlines, inline)
+				}
+			}
+			text = text[n:]
+		} else {
+			next := strings.IndexAny(text, "`*$!@[<^{\n")
+			if next == -1 {
+				inlines = appendText(inlines, text)
+				break
+			}
+			if next == 0 {
+				// 未构成内联元素的特殊字符按普通文本处理
+				inlines = appendText(inlines, text[:1])
+				text = text[1:]
+				continue
+			}
+			inlines = appendText(inlines, text[:next])
+			text = text[next:]
+		}
+	}
+	return inlines
+}
+
+// parseCodeSpan 解析由相同长度的反引号串包围的行内代码，返回代码和消耗的字节数；
+// 找不到结束的反引号串时返回起始反引号串的长度
+func parseCodeSpan(text string) (models.Code, int, bool) {
+	open := len(text) - len(strings.TrimLeft(text, "`"))
+	for i := open; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		if run == open {
+			content := strings.ReplaceAll(text[open:i], "\n", " ")
+			// 两端各有一个空格且内容不全是空格时去掉这对空格，使代码可以以反引号开头
+			if len(content) >= 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+				content = content[1 : len(content)-1]
+			}
+			return models.Code{Content: content}, i + run, true
+		}
+		i += run
+	}
+	return models.Code{}, open, false
+}
+
+// parseImage 解析 ![alt](src "title"){attrs} 或 ![alt][ref] 形式的图片，返回图片和消耗的字节数
+func (p *markdownParser) parseImage(text string) (models.Image, int, bool) {
+	altEnd := matchingBracket(text, 1, '[', ']')
+	if altEnd == -1 {
+		return models.Image{}, 0, false
+	}
+	src, title, n, ok := p.parseLinkTarget(text, 1, altEnd)
+	if !ok {
+		return models.Image{}, 0, false
+	}
+	image := models.Image{Src: src, Alt: text[2:altEnd], Title: title}
+	if attr, size, ok := parseAttributes(text[n:]); ok {
+		image.Attr = attr
+		n += size
+	}
+	return image, n, true
+}
+
+// parseLink 解析行内链接 [text](url "title") 和引用链接 [text][ref]、[text][]、[ref]
+func (p *markdownParser) parseLink(text string) (models.Link, int, bool) {
+	textEnd := matchingBracket(text, 0, '[', ']')
+	if textEnd == -1 {
+		return models.Link{}, 0, false
+	}
+	url, title, n, ok := p.parseLinkTarget(text, 0, textEnd)
+	if !ok {
+		return models.Link{}, 0, false
+	}
+	return models.Link{Content: p.parseInlines(text[1:textEnd]), URL: url, Title: title}, n, true
+}
+
+// parseLinkTarget 解析链接文本之后的目标，open和close为链接文本方括号的位置，返回地址、标题和消耗的字节数
+func (p *markdownParser) parseLinkTarget(text string, open, close int) (string, string, int, bool) {
+	next := close + 1
+	if next < len(text) && text[next] == '(' {
+		destEnd := matchingBracket(text, next, '(', ')')
+		if destEnd == -1 {
+			return "", "", 0, false
+		}
+		dest := strings.TrimSpace(text[next+1 : destEnd])
+		title := ""
+		if i := strings.IndexAny(dest, " \t"); i != -1 {
+			title = strings.Trim(strings.TrimSpace(dest[i:]), `"'`)
+			dest = dest[:i]
+		}
+		return strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">"), title, destEnd + 1, true
+	}
+	label, end := text[open+1:close], close+1
+	if next < len(text) && text[next] == '[' {
+		refEnd := matchingBracket(text, next, '[', ']')
+		if refEnd == -1 {
+			return "", "", 0, false
+		}
+		if ref := text[next+1 : refEnd]; ref != "" {
+			label = ref
+		}
+		end = refEnd + 1
+	}
+	def, ok := p.links[normalizeLabel(label)]
+	if !ok {
+		return "", "", 0, false
+	}
+	return def.url, def.title, end, true
+}
+
+// matchingBracket 返回与text[open]处的左括号匹配的右括号位置，找不到时返回-1
+func matchingBracket(text string, open int, left, right byte) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case left:
+			depth++
+		case right:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isLabelChar 判断字符是否可以出现在交叉引用的标识符中
+func isLabelChar(c byte) bool {
+	return c == '-' || c == '_' || c == ':' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// endsWithWordChar 判断已解析的内容是否以字母或数字结尾，用于排除邮箱地址
+func endsWithWordChar(inlines []models.Inline) bool {
+	if n := len(inlines); n > 0 {
+		if last, ok := inlines[n-1].(models.Text); ok && last.Content != "" {
+			return isLabelChar(last.Content[len(last.Content)-1])
+		}
+	}
+	return false
+}
+
+// appendText 追加文本，与前一个文本元素相邻时合并
+func appendText(inlines []models.Inline, text string) []models.Inline {
+	if n := len(inlines); n > 0 {
+		if last, ok := inlines[n-1].(models.Text); ok {
+			inlines[n-1] = models.Text{Content: last.Content + text}
+			return inlines
+		}
+	}
+	return append(inlines, models.Text{Content: text})
+}
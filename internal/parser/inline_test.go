@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseLinks(t *testing.T) {
+	md := "参见[官方**文档**](https://go.dev/doc \"Go 文档\")、[规范][spec]、[Spec][]、[spec]、[未定义][none]、" +
+		"<https://example.com/a?b=c>、<dev@example.com>和[标题](#简介)。\n\n" +
+		"[spec]: https://go.dev/ref/spec \"语言规范\"\n"
+	doc := ParseMarkdown(md)
+
+	if len(doc.Blocks) != 1 {
+		t.Fatalf("链接定义行应被移除，期望解析出1个块元素，实际为%d", len(doc.Blocks))
+	}
+	paragraph := doc.Blocks[0].(models.Paragraph)
+	var links []models.Link
+	for _, inline := range paragraph.Inlines {
+		if link, ok := inline.(models.Link); ok {
+			links = append(links, link)
+		}
+	}
+	expected := []struct {
+		url   string
+		title string
+		text  string
+	}{
+		{"https://go.dev/doc", "Go 文档", "官方文档"},
+		{"https://go.dev/ref/spec", "语言规范", "规范"},
+		{"https://go.dev/ref/spec", "语言规范", "Spec"},
+		{"https://go.dev/ref/spec", "语言规范", "spec"},
+		{"https://example.com/a?b=c", "", "https://example.com/a?b=c"},
+		{"mailto:dev@example.com", "", "dev@example.com"},
+		{"#简介", "", "标题"},
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("期望解析出%d个链接，实际为%d: %#v", len(expected), len(links), paragraph.Inlines)
+	}
+	for i, e := range expected {
+		if links[i].URL != e.url || links[i].Title != e.title || models.PlainText(links[i].Content) != e.text {
+			t.Errorf("第%d个链接解析错误，期望为%+v，实际为%+v", i+1, e, links[i])
+		}
+	}
+	if bold, ok := links[0].Content[1].(models.Bold); !ok || models.PlainText(bold.Content) != "文档" {
+		t.Errorf("链接文本中的粗体应被解析，实际为%#v", links[0].Content)
+	}
+	if text := models.PlainText(paragraph.Inlines); !strings.Contains(text, "[未定义][none]") {
+		t.Errorf("未定义的引用链接应保留为文本，实际为'%s'", text)
+	}
+}
+
+func TestCollectLinkDefinitions(t *testing.T) {
+	p := &markdownParser{links: map[string]linkDefinition{}}
+	lines := p.collectLinkDefinitions([]string{
+		"[Foo  Bar]: <https://foo.example> 'Foo'",
+		"```",
+		"[code]: https://code.example",
+		"```",
+		"[foo bar]: https://ignored.example",
+	})
+	if !reflect.DeepEqual(lines, []string{"```", "[code]: https://code.example", "```"}) {
+		t.Errorf("代码块中的内容不应被当作链接定义，实际剩余行为%v", lines)
+	}
+	if def := p.links["foo bar"]; def.url != "https://foo.example" || def.title != "Foo" {
+		t.Errorf("标签应规范化且第一个定义优先，实际为%+v", def)
+	}
+}
+
+func TestParseCodeSpans(t *testing.T) {
+	doc := ParseMarkdown("用 `**不解析**` 和 `` a`b `` 以及 ` `` ` 表示，未闭合的 ``x` 保持原样")
+	var codes []string
+	for _, inline := range doc.Blocks[0].(models.Paragraph).Inlines {
+		if code, ok := inline.(models.Code); ok {
+			codes = append(codes, code.Content)
+		}
+	}
+	expected := []string{"**不解析**", "a`b", "``"}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("行内代码解析错误，期望为%q，实际为%q", expected, codes)
+	}
+	if text := models.PlainText(doc.Blocks[0].(models.Paragraph).Inlines); !strings.HasSuffix(text, "未闭合的 ``x` 保持原样") {
+		t.Errorf("未闭合的反引号应保留为文本，实际为'%s'", text)
+	}
+}
@@ -2,14 +2,56 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"goffice/internal/models"
 )
 
+// markdownParser 保存解析过程中跨块共享的状态
+type markdownParser struct {
+	links         map[string]linkDefinition // 链接引用定义，键为规范化后的标签
+	footnotes     []models.Footnote         // 脚注定义，包括行内脚注
+	footnoteLines map[string][]string       // 脚注定义的原始文本行，键为脚注标签
+	inlineNotes   int                       // 已解析的行内脚注数量
+	warnings      []string                  // 解析过程中发现的输入问题，如无法解析的YAML元数据
+}
+
 // ParseMarkdown 将Markdown文本解析为文档模型
 func ParseMarkdown(md string) models.Document {
-	lines := strings.Split(md, "\n")
+	doc, _ := ParseMarkdownWithWarnings(md)
+	return doc
+}
+
+// ParseMarkdownWithWarnings 将Markdown文本解析为文档模型，同时返回解析过程中的警告，
+// 如无法解析的YAML元数据和不支持的HTML标签。有警告时仍尽量解析其余内容
+func ParseMarkdownWithWarnings(md string) (models.Document, []string) {
+	p := &markdownParser{links: map[string]linkDefinition{}, footnoteLines: map[string][]string{}}
+	meta, lines, err := parseFrontMatter(strings.Split(md, "\n"))
+	if err != nil {
+		p.warn("解析YAML元数据失败，按正文处理: %v", err)
+	}
+	lines = p.collectFootnotes(lines)
+	lines = p.collectLinkDefinitions(lines)
+	p.parseFootnotes()
+	blocks := p.parseBlocks(lines)
+	return models.Document{Blocks: blocks, Footnotes: p.footnotes, Metadata: meta}, p.warnings
+}
+
+// warn 记录一条解析警告
+func (p *markdownParser) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// parseText 解析一段嵌套的Markdown文本，如表格单元格的内容
+func (p *markdownParser) parseText(text string) []models.Block {
+	return p.parseBlocks(strings.Split(text, "\n"))
+}
+
+// parseBlocks 将文本行解析为块元素
+func (p *markdownParser) parseBlocks(lines []string) []models.Block {
 	var blocks []models.Block
 	var currentLines []string
 	var inMathBlock bool = false
@@ -18,11 +60,28 @@ func ParseMarkdown(md string) models.Document {
 	for i := 0; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
+		// 段落行保留行尾空格，用于识别硬换行
+		content := strings.TrimLeft(line, " \t")
+
+		// 原样输出的代码块，如 ```{=openxml}
+		if m := rawFencePattern.FindStringSubmatch(trimmed); m != nil && !inMathBlock {
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			end := i + 1
+			for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "```") {
+				end++
+			}
+			blocks = append(blocks, models.RawBlock{Format: m[1], Content: strings.Join(lines[i+1:end], "\n")})
+			i = end
+			continue
+		}
 
 		// 检测数学代码块开始
 		if strings.HasPrefix(trimmed, "```math") {
 			if len(currentLines) > 0 {
-				blocks = append(blocks, parseParagraph(strings.Join(currentLines, " ")))
+				blocks = append(blocks, p.parseTextBlock(currentLines))
 				currentLines = nil
 			}
 			inMathBlock = true
@@ -50,67 +109,337 @@ func ParseMarkdown(md string) models.Document {
 		// 正常Markdown解析
 		if trimmed == "" {
 			if len(currentLines) > 0 {
-				blocks = append(blocks, parseParagraph(strings.Join(currentLines, " ")))
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+		} else if level := setextLevel(line); level > 0 && len(currentLines) > 0 {
+			// 段落之后的 === 或 --- 将段落变为标题
+			blocks = append(blocks, p.header(level, joinLines(currentLines)))
+			currentLines = nil
+		} else if strings.HasPrefix(trimmed, ">") {
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			quote, next := p.parseBlockQuote(lines, i)
+			blocks = append(blocks, quote)
+			i = next - 1
+		} else if section, ok := sectionBreak(trimmed); ok {
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			blocks = append(blocks, section)
+		} else if isPageBreak(trimmed) {
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
 				currentLines = nil
 			}
-		} else if strings.HasPrefix(trimmed, "#") {
+			blocks = append(blocks, models.PageBreak{})
+		} else if isColumnBreak(trimmed) {
 			if len(currentLines) > 0 {
-				blocks = append(blocks, parseParagraph(strings.Join(currentLines, " ")))
+				blocks = append(blocks, p.parseTextBlock(currentLines))
 				currentLines = nil
 			}
-			level := 0
-			for _, c := range trimmed {
-				if c == '#' {
-					level++
-				} else {
-					break
+			blocks = append(blocks, models.ColumnBreak{})
+		} else if isHorizontalRule(trimmed) {
+			if len(currentLines) == 0 {
+				// 多行表格以整行短横线开头
+				if table, next, ok := p.parseTable(lines, i); ok {
+					blocks = append(blocks, table)
+					i = next - 1
+					continue
 				}
 			}
-			text := strings.TrimSpace(trimmed[level:])
-			blocks = append(blocks, models.Header{Level: level, Text: text})
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			blocks = append(blocks, models.HorizontalRule{})
+		} else if level, text, ok := atxHeading(line); ok {
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			blocks = append(blocks, p.header(level, text))
+		} else if strings.HasPrefix(trimmed, ":::") {
+			if div, next, ok := p.parseFencedDiv(lines, i); ok {
+				if len(currentLines) > 0 {
+					blocks = append(blocks, p.parseTextBlock(currentLines))
+					currentLines = nil
+				}
+				blocks = append(blocks, div)
+				i = next - 1
+				continue
+			}
+			currentLines = append(currentLines, content)
+		} else if len(currentLines) == 0 && trimmed == "[LOF]" {
+			blocks = append(blocks, models.ListOfFigures{})
+		} else if len(currentLines) == 0 && (trimmed == "[TOC]" || trimmed == "[[_TOC_]]") {
+			blocks = append(blocks, models.TableOfContents{})
+		} else if strings.HasPrefix(trimmed, "<!--") && commentEnd(lines, i) != -1 {
+			// 单独成块的HTML注释不输出
+			if len(currentLines) > 0 {
+				blocks = append(blocks, p.parseTextBlock(currentLines))
+				currentLines = nil
+			}
+			i = commentEnd(lines, i)
+		} else if len(currentLines) == 0 && isHTMLBlockStart(trimmed) {
+			if block, next, ok := p.parseHTMLBlock(lines, i); ok {
+				blocks = append(blocks, block)
+				i = next - 1
+				continue
+			}
+			currentLines = append(currentLines, content)
+		} else if len(currentLines) == 0 {
+			if list, next, ok := p.parseDefinitionList(lines, i); ok {
+				blocks = append(blocks, list)
+				i = next - 1
+				continue
+			}
+			// 表格只能出现在块的开头
+			if table, next, ok := p.parseTable(lines, i); ok {
+				blocks = append(blocks, table)
+				i = next - 1
+				continue
+			}
+			currentLines = append(currentLines, content)
 		} else {
-			currentLines = append(currentLines, trimmed)
+			currentLines = append(currentLines, content)
 		}
 	}
 	if len(currentLines) > 0 {
-		blocks = append(blocks, parseParagraph(strings.Join(currentLines, " ")))
-	}
-	return models.Document{Blocks: blocks}
-}
-
-// parseParagraph 解析段落文本，识别内联元素
-func parseParagraph(text string) models.Paragraph {
-	var inlines []models.Inline
-	for len(text) > 0 {
-		if strings.HasPrefix(text, "**") {
-			text = text[2:]
-			end := strings.Index(text, "**")
-			if end == -1 {
-				inlines = append(inlines, models.Text{Content: text})
-				break
-			}
-			inlines = append(inlines, models.Bold{Content: []models.Inline{models.Text{Content: text[:end]}}})
-			text = text[end+2:]
-		} else if strings.HasPrefix(text, "$") {
-			text = text[1:]
-			end := strings.Index(text, "$")
-			if end == -1 {
-				inlines = append(inlines, models.Text{Content: text})
-				break
-			}
-			inlines = append(inlines, models.Math{LaTeX: text[:end], Display: false})
-			text = text[end+1:]
-		} else {
-			next := strings.IndexAny(text, "*$")
-			if next == -1 {
-				inlines = append(inlines, models.Text{Content: text})
-				break
+		blocks = append(blocks, p.parseTextBlock(currentLines))
+	}
+	return blocks
+}
+
+// rawFencePattern 匹配原样输出代码块的起始行，如 ```{=openxml}
+var rawFencePattern = regexp.MustCompile("^```\\s*\\{=([A-Za-z][\\w-]*)\\}\\s*$")
+
+// calloutPattern 匹配引用块首行的提示标记，如 [!NOTE] 或 Obsidian 风格的 [!tip]- 标题
+var calloutPattern = regexp.MustCompile(`^\[!([A-Za-z][\w-]*)\][+-]?\s*(.*)$`)
+
+// fencedDivPattern 匹配提示块的起始行，如 ::: tip、:::warning 标题 或 ::: {.note}
+var fencedDivPattern = regexp.MustCompile(`^(:{3,})\s*([A-Za-z][\w-]*|\{[^}]*\})\s*(.*?)\s*:*$`)
+
+// parseBlockQuote 解析从第start行开始的引用块，首行为提示标记时返回提示块，同时返回之后的行号
+func (p *markdownParser) parseBlockQuote(lines []string, start int) (models.Block, int) {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, ">") {
+			content := strings.TrimPrefix(strings.TrimLeftFunc(lines[i], unicode.IsSpace), ">")
+			inner = append(inner, strings.TrimPrefix(content, " "))
+			continue
+		}
+		// 段落的延续行可以省略 ">"，分隔线结束引用块，不作为引用中的setext标题
+		last := inner[len(inner)-1]
+		if trimmed == "" || strings.TrimSpace(last) == "" || isHeading(lines[i]) || isHorizontalRule(trimmed) || strings.HasPrefix(trimmed, "```") {
+			break
+		}
+		inner = append(inner, strings.TrimLeft(lines[i], " \t"))
+	}
+	if m := calloutPattern.FindStringSubmatch(inner[0]); m != nil {
+		return models.Callout{Kind: strings.ToLower(m[1]), Title: p.parseInlines(m[2]), Blocks: p.parseBlocks(inner[1:])}, i
+	}
+	return models.BlockQuote{Blocks: p.parseBlocks(inner)}, i
+}
+
+// parseFencedDiv 解析 ::: kind 标题 与 ::: 之间的提示块，支持嵌套，返回提示块和之后的行号
+func (p *markdownParser) parseFencedDiv(lines []string, start int) (models.Block, int, bool) {
+	m := fencedDivPattern.FindStringSubmatch(strings.TrimSpace(lines[start]))
+	if m == nil {
+		return nil, start, false
+	}
+	kind, title := m[2], m[3]
+	if strings.HasPrefix(kind, "{") {
+		attr, _, ok := parseAttributes(kind)
+		if !ok || len(attr.Classes) == 0 {
+			return nil, start, false
+		}
+		kind = attr.Classes[0]
+	}
+	depth := 1
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if fencedDivPattern.MatchString(trimmed) {
+			depth++
+		} else if strings.HasPrefix(trimmed, ":::") && strings.Trim(trimmed, ":") == "" {
+			depth--
+			if depth == 0 && strings.EqualFold(kind, "columns") {
+				return columns(m[2], title, p.parseBlocks(lines[start+1:i])), i + 1, true
 			}
-			if next > 0 {
-				inlines = append(inlines, models.Text{Content: text[:next]})
+			if depth == 0 {
+				return models.Callout{
+					Kind:   strings.ToLower(kind),
+					Title:  p.parseInlines(strings.TrimSpace(title)),
+					Blocks: p.parseBlocks(lines[start+1 : i]),
+				}, i + 1, true
 			}
-			text = text[next:]
 		}
 	}
-	return models.Paragraph{Inlines: inlines}
+	return nil, start, false
+}
+
+// columns 创建分栏块。栏数和栏间距写在 ::: columns 3 之后，或写作 ::: {.columns count=3 gap=8mm}，默认分两栏
+func columns(kind, title string, blocks []models.Block) models.Columns {
+	c := models.Columns{Count: 2, Blocks: blocks}
+	count := strings.TrimSpace(title)
+	if strings.HasPrefix(kind, "{") {
+		attr, _, _ := parseAttributes(kind)
+		count, c.Gap = attr.Get("count"), attr.Get("gap")
+	}
+	if n, err := strconv.Atoi(count); err == nil && n > 0 {
+		c.Count = n
+	}
+	return c
+}
+
+// parseTextBlock 解析段落行，只包含一张带替代文本的图片时作为图处理
+func (p *markdownParser) parseTextBlock(lines []string) models.Block {
+	paragraph := p.parseParagraph(joinLines(lines))
+	if len(paragraph.Inlines) == 1 {
+		if image, ok := paragraph.Inlines[0].(models.Image); ok && image.Alt != "" {
+			return models.Figure{Image: image, Caption: p.parseInlines(image.Alt)}
+		}
+	}
+	return paragraph
+}
+
+// header 由标题文本创建标题，文本末尾可以有 {#id .unnumbered} 形式的属性
+func (p *markdownParser) header(level int, text string) models.Header {
+	text, attr := trailingAttributes(text)
+	return models.Header{Level: level, Inlines: p.parseInlines(text), Attr: attr}
+}
+
+// atxHeading 按CommonMark规则解析 # 标题：最多缩进3个空格，1到6个 #，其后必须是空白或行尾，
+// 结尾由空白分隔的 # 序列不属于标题内容
+func atxHeading(line string) (int, string, bool) {
+	rest := strings.TrimLeft(line, " ")
+	if len(line)-len(rest) > 3 {
+		return 0, "", false
+	}
+	level := 0
+	for level < len(rest) && rest[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest = rest[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(rest)
+	if closing := strings.TrimRight(text, "#"); closing == "" || strings.HasSuffix(closing, " ") || strings.HasSuffix(closing, "\t") {
+		text = strings.TrimSpace(closing)
+	}
+	return level, text, true
+}
+
+// isHeading 判断行是否为 # 标题
+func isHeading(line string) bool {
+	_, _, ok := atxHeading(line)
+	return ok
+}
+
+// setextLevel 判断行是否为Setext标题的下划线，=== 为一级，--- 为二级，不是时返回0
+func setextLevel(line string) int {
+	rest := strings.TrimLeft(line, " ")
+	if len(line)-len(rest) > 3 {
+		return 0
+	}
+	rest = strings.TrimRight(rest, " \t")
+	switch {
+	case rest == "":
+		return 0
+	case strings.Trim(rest, "=") == "":
+		return 1
+	case strings.Trim(rest, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// commentEnd 返回从第start行开始的HTML注释结束的行号，注释之后同一行还有内容时返回-1
+func commentEnd(lines []string, start int) int {
+	for i := start; i < len(lines); i++ {
+		from := 0
+		if i == start {
+			from = strings.Index(lines[i], "<!--") + 4
+		}
+		if end := strings.Index(lines[i][from:], "-->"); end != -1 {
+			if strings.TrimSpace(lines[i][from+end+3:]) != "" {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// pageBreakPattern 匹配 <!-- pagebreak --> 形式的分页标记
+var pageBreakPattern = regexp.MustCompile(`^<!--\s*pagebreak\s*-->$`)
+
+// isPageBreak 判断行是否为分页标记：\newpage 或 <!-- pagebreak -->
+func isPageBreak(trimmed string) bool {
+	return trimmed == `\newpage` || pageBreakPattern.MatchString(trimmed)
+}
+
+// columnBreakPattern 匹配 <!-- columnbreak --> 形式的分栏标记
+var columnBreakPattern = regexp.MustCompile(`^<!--\s*columnbreak\s*-->$`)
+
+// isColumnBreak 判断行是否为分栏标记：\columnbreak 或 <!-- columnbreak -->
+func isColumnBreak(trimmed string) bool {
+	return trimmed == `\columnbreak` || columnBreakPattern.MatchString(trimmed)
+}
+
+// sectionBreakPattern 匹配 <!-- section --> 形式的分节标记，可指定新节的纸张方向
+var sectionBreakPattern = regexp.MustCompile(`^<!--\s*section(?:\s+(landscape|portrait))?\s*-->$`)
+
+// sectionBreak 解析分节标记，如 <!-- section landscape --> 开始横向的新节
+func sectionBreak(trimmed string) (models.SectionBreak, bool) {
+	m := sectionBreakPattern.FindStringSubmatch(trimmed)
+	if m == nil {
+		return models.SectionBreak{}, false
+	}
+	return models.SectionBreak{Orientation: m[1]}, true
+}
+
+// isHorizontalRule 判断行是否为分隔线：至少3个相同的 -、* 或 _，其间可以有空格
+func isHorizontalRule(trimmed string) bool {
+	if trimmed == "" || !strings.ContainsRune("-*_", rune(trimmed[0])) {
+		return false
+	}
+	count := 0
+	for _, c := range trimmed {
+		if c == rune(trimmed[0]) {
+			count++
+		} else if c != ' ' && c != '\t' {
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// joinLines 将段落行合并为一行：以两个以上空格或反斜杠结尾的行之后为硬换行，用换行符表示，其他行之间以空格连接
+func joinLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i == len(lines)-1 {
+			b.WriteString(strings.TrimRight(line, " \t"))
+			break
+		}
+		switch {
+		case strings.HasSuffix(line, "  "):
+			b.WriteString(strings.TrimRight(line, " \t") + "\n")
+		case strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`):
+			b.WriteString(strings.TrimSuffix(line, `\`) + "\n")
+		default:
+			b.WriteString(strings.TrimRight(line, " \t") + " ")
+		}
+	}
+	return b.String()
 }
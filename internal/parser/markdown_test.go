@@ -19,8 +19,8 @@ func TestParseMarkdown(t *testing.T) {
 
 		// 验证第一个标题
 		if header, ok := doc.Blocks[0].(models.Header); ok {
-			if header.Level != 1 || header.Text != "一级标题" {
-				t.Errorf("第一个标题解析错误，期望为一级标题'一级标题'，实际为%d级'%s'", header.Level, header.Text)
+			if header.Level != 1 || models.PlainText(header.Inlines) != "一级标题" {
+				t.Errorf("第一个标题解析错误，期望为一级标题'一级标题'，实际为%d级'%s'", header.Level, models.PlainText(header.Inlines))
 			}
 		} else {
 			t.Errorf("第一个元素应为Header类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
@@ -28,8 +28,8 @@ func TestParseMarkdown(t *testing.T) {
 
 		// 验证第二个标题
 		if header, ok := doc.Blocks[1].(models.Header); ok {
-			if header.Level != 2 || header.Text != "二级标题" {
-				t.Errorf("第二个标题解析错误，期望为二级标题'二级标题'，实际为%d级'%s'", header.Level, header.Text)
+			if header.Level != 2 || models.PlainText(header.Inlines) != "二级标题" {
+				t.Errorf("第二个标题解析错误，期望为二级标题'二级标题'，实际为%d级'%s'", header.Level, models.PlainText(header.Inlines))
 			}
 		} else {
 			t.Errorf("第二个元素应为Header类型，实际为%s", reflect.TypeOf(doc.Blocks[1]))
@@ -37,8 +37,8 @@ func TestParseMarkdown(t *testing.T) {
 
 		// 验证第三个标题
 		if header, ok := doc.Blocks[2].(models.Header); ok {
-			if header.Level != 3 || header.Text != "三级标题" {
-				t.Errorf("第三个标题解析错误，期望为三级标题'三级标题'，实际为%d级'%s'", header.Level, header.Text)
+			if header.Level != 3 || models.PlainText(header.Inlines) != "三级标题" {
+				t.Errorf("第三个标题解析错误，期望为三级标题'三级标题'，实际为%d级'%s'", header.Level, models.PlainText(header.Inlines))
 			}
 		} else {
 			t.Errorf("第三个元素应为Header类型，实际为%s", reflect.TypeOf(doc.Blocks[2]))
@@ -198,4 +198,252 @@ func TestParseMarkdown(t *testing.T) {
 			t.Errorf("块元素应为Math类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
 		}
 	})
+
+	// 测试案例6：解析图片
+	t.Run("解析图片", func(t *testing.T) {
+		md := "架构如下：![系统架构](images/arch.png \"架构图\"){width=50%}所示"
+		doc := ParseMarkdown(md)
+
+		paragraph, ok := doc.Blocks[0].(models.Paragraph)
+		if !ok || len(paragraph.Inlines) != 3 {
+			t.Fatalf("期望解析出包含3个内联元素的段落，实际为%#v", doc.Blocks[0])
+		}
+		if image, ok := paragraph.Inlines[1].(models.Image); ok {
+			if image.Src != "images/arch.png" || image.Alt != "系统架构" || image.Title != "架构图" {
+				t.Errorf("图片解析错误，实际为%+v", image)
+			}
+			if image.Attr.Get("width") != "50%" {
+				t.Errorf("图片宽度属性解析错误，实际为'%s'", image.Attr.Get("width"))
+			}
+		} else {
+			t.Errorf("第二部分应为Image类型，实际为%s", reflect.TypeOf(paragraph.Inlines[1]))
+		}
+		if text, ok := paragraph.Inlines[2].(models.Text); !ok || text.Content != "所示" {
+			t.Errorf("图片后的文本解析错误，实际为%#v", paragraph.Inlines[2])
+		}
+	})
+
+	// 测试案例7：不构成图片的感叹号保留为文本
+	t.Run("感叹号保留为文本", func(t *testing.T) {
+		doc := ParseMarkdown("注意！这不是![图片]而是*文本")
+		paragraph := doc.Blocks[0].(models.Paragraph)
+		if len(paragraph.Inlines) != 1 {
+			t.Fatalf("期望段落包含1个内联元素，实际为%d", len(paragraph.Inlines))
+		}
+		if text := paragraph.Inlines[0].(models.Text); text.Content != "注意！这不是![图片]而是*文本" {
+			t.Errorf("文本解析错误，实际为'%s'", text.Content)
+		}
+	})
+
+	// 测试案例8：解析图和交叉引用
+	t.Run("解析图和交叉引用", func(t *testing.T) {
+		md := "[LOF]\n\n![系统**架构**](arch.png){#fig:arch}\n\n如@fig:arch。所示，联系a@fig:b"
+		doc := ParseMarkdown(md)
+
+		if len(doc.Blocks) != 3 {
+			t.Fatalf("期望解析出3个块元素，实际为%d", len(doc.Blocks))
+		}
+		if _, ok := doc.Blocks[0].(models.ListOfFigures); !ok {
+			t.Errorf("[LOF]应解析为ListOfFigures，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if figure, ok := doc.Blocks[1].(models.Figure); ok {
+			if figure.Image.Attr.ID != "fig:arch" || len(figure.Caption) != 2 {
+				t.Errorf("图解析错误，实际为%+v", figure)
+			}
+		} else {
+			t.Errorf("单独成段的图片应解析为Figure，实际为%s", reflect.TypeOf(doc.Blocks[1]))
+		}
+		paragraph := doc.Blocks[2].(models.Paragraph)
+		if len(paragraph.Inlines) != 3 {
+			t.Fatalf("期望段落包含3个内联元素，实际为%#v", paragraph.Inlines)
+		}
+		if ref, ok := paragraph.Inlines[1].(models.Reference); !ok || ref.ID != "fig:arch" {
+			t.Errorf("交叉引用解析错误，实际为%#v", paragraph.Inlines[1])
+		}
+		if text := paragraph.Inlines[2].(models.Text); text.Content != "。所示，联系a@fig:b" {
+			t.Errorf("邮箱形式的文本不应解析为交叉引用，实际为'%s'", text.Content)
+		}
+	})
+
+	// 测试案例9：解析嵌套的引用块
+	t.Run("解析嵌套的引用块", func(t *testing.T) {
+		md := "> 第一段\n延续行\n>\n> > 嵌套引用\n> ```math\n> x^2\n> ```\n\n引用之后的段落"
+		doc := ParseMarkdown(md)
+
+		if len(doc.Blocks) != 2 {
+			t.Fatalf("期望解析出2个块元素，实际为%d", len(doc.Blocks))
+		}
+		quote, ok := doc.Blocks[0].(models.BlockQuote)
+		if !ok {
+			t.Fatalf("第一个元素应为BlockQuote类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if len(quote.Blocks) != 3 {
+			t.Fatalf("引用块应包含段落、嵌套引用和数学公式，实际为%#v", quote.Blocks)
+		}
+		if text := models.PlainText(quote.Blocks[0].(models.Paragraph).Inlines); text != "第一段 延续行" {
+			t.Errorf("省略 > 的延续行应并入段落，实际为'%s'", text)
+		}
+		if nested, ok := quote.Blocks[1].(models.BlockQuote); !ok || len(nested.Blocks) != 1 {
+			t.Errorf("嵌套引用解析错误，实际为%#v", quote.Blocks[1])
+		}
+		if math, ok := quote.Blocks[2].(models.Math); !ok || math.LaTeX != "x^2" {
+			t.Errorf("引用块中的数学公式解析错误，实际为%#v", quote.Blocks[2])
+		}
+		// 分隔线结束引用块，不作为延续行
+		if doc := ParseMarkdown("> 引用\n---"); len(doc.Blocks) != 2 || doc.Blocks[1].Type() != "horizontalrule" {
+			t.Errorf("引用之后的分隔线解析错误，实际为%#v", doc.Blocks)
+		}
+		// 行首的其他空白字符不应导致无限递归
+		if doc := ParseMarkdown("\v> 引用"); len(doc.Blocks) != 1 || doc.Blocks[0].Type() != "blockquote" {
+			t.Errorf("以垂直制表符开头的引用解析错误，实际为%#v", doc.Blocks)
+		}
+	})
+
+	// 测试案例10：解析分隔线、硬换行和分页符
+	t.Run("解析分隔线和换行", func(t *testing.T) {
+		md := "第一行  \n第二行\\\n第三行\n第四行\n***\n\n_ _ _\n\\newpage\n<!-- pagebreak -->\n结尾"
+		doc := ParseMarkdown(md)
+
+		want := []string{"paragraph", "horizontalrule", "horizontalrule", "pagebreak", "pagebreak", "paragraph"}
+		if len(doc.Blocks) != len(want) {
+			t.Fatalf("期望解析出%d个块元素，实际为%#v", len(want), doc.Blocks)
+		}
+		for i, block := range doc.Blocks {
+			if block.Type() != want[i] {
+				t.Errorf("第%d个块元素应为%s，实际为%s", i+1, want[i], block.Type())
+			}
+		}
+		inlines := doc.Blocks[0].(models.Paragraph).Inlines
+		expected := []models.Inline{
+			models.Text{Content: "第一行"}, models.LineBreak{},
+			models.Text{Content: "第二行"}, models.LineBreak{},
+			models.Text{Content: "第三行 第四行"},
+		}
+		if !reflect.DeepEqual(inlines, expected) {
+			t.Errorf("硬换行解析错误，实际为%#v", inlines)
+		}
+	})
+
+	// 测试案例11：按CommonMark规则解析标题
+	t.Run("解析ATX和Setext标题", func(t *testing.T) {
+		md := "#hashtag 不是标题\n\n## 关闭序列 ##\n####### 七级\n# C\\#\n#\n" +
+			"带 **粗体** 的\n标题\n====\n二级 $x^2$ 与 `code`\n---"
+		doc := ParseMarkdown(md)
+
+		expected := []struct {
+			level int
+			text  string
+		}{{0, "#hashtag 不是标题"}, {2, "关闭序列"}, {0, "####### 七级"}, {1, "C\\#"}, {1, ""}, {1, "带 粗体 的 标题"}, {2, "二级 x^2 与 code"}}
+		if len(doc.Blocks) != len(expected) {
+			t.Fatalf("期望解析出%d个块元素，实际为%#v", len(expected), doc.Blocks)
+		}
+		for i, e := range expected {
+			switch b := doc.Blocks[i].(type) {
+			case models.Header:
+				if b.Level != e.level || models.PlainText(b.Inlines) != e.text {
+					t.Errorf("第%d个块应为%d级标题'%s'，实际为%d级'%s'", i+1, e.level, e.text, b.Level, models.PlainText(b.Inlines))
+				}
+			case models.Paragraph:
+				if e.level != 0 || models.PlainText(b.Inlines) != e.text {
+					t.Errorf("第%d个块应为段落'%s'，实际为'%s'", i+1, e.text, models.PlainText(b.Inlines))
+				}
+			}
+		}
+		last := doc.Blocks[6].(models.Header)
+		if _, ok := last.Inlines[1].(models.Math); !ok {
+			t.Errorf("标题中的公式应被解析，实际为%#v", last.Inlines)
+		}
+		if code, ok := last.Inlines[3].(models.Code); !ok || code.Content != "code" {
+			t.Errorf("标题中的行内代码应被解析，实际为%#v", last.Inlines)
+		}
+	})
+
+	// 测试案例12：原样输出的OpenXML
+	t.Run("解析原始OpenXML", func(t *testing.T) {
+		md := "前文\n```{=openxml}\n<w:p>\n# 不是标题\n</w:p>\n```\n插入 `<w:r><w:t>域</w:t></w:r>`{=openxml} 和 `普通代码`"
+		doc := ParseMarkdown(md)
+
+		if len(doc.Blocks) != 3 {
+			t.Fatalf("期望解析出3个块元素，实际为%#v", doc.Blocks)
+		}
+		raw, ok := doc.Blocks[1].(models.RawBlock)
+		if !ok || raw.Format != "openxml" || raw.Content != "<w:p>\n# 不是标题\n</w:p>" {
+			t.Errorf("原始代码块解析错误，实际为%#v", doc.Blocks[1])
+		}
+		inlines := doc.Blocks[2].(models.Paragraph).Inlines
+		if r, ok := inlines[1].(models.RawInline); !ok || r.Content != "<w:r><w:t>域</w:t></w:r>" {
+			t.Errorf("原始行内内容解析错误，实际为%#v", inlines[1])
+		}
+		if _, ok := inlines[3].(models.Code); !ok {
+			t.Errorf("没有 {=format} 的行内代码不应原样输出，实际为%#v", inlines[3])
+		}
+	})
+
+	// 测试案例13：目录标记
+	t.Run("解析目录标记", func(t *testing.T) {
+		doc := ParseMarkdown("[TOC]\n\n[[_TOC_]]\n\n正文 [TOC]")
+		if len(doc.Blocks) != 3 || doc.Blocks[0].Type() != "tableofcontents" || doc.Blocks[1].Type() != "tableofcontents" {
+			t.Errorf("目录标记解析错误，实际为%#v", doc.Blocks)
+		}
+		if doc.Blocks[2].Type() != "paragraph" {
+			t.Error("段落中的 [TOC] 不是目录标记")
+		}
+	})
+
+	// 测试案例14：解析分节标记
+	t.Run("解析分节标记", func(t *testing.T) {
+		md := "正文\n<!-- section landscape -->\n宽表格\n\n<!--section portrait-->\n<!-- section -->\n<!-- section sideways -->"
+		doc := ParseMarkdown(md)
+
+		if len(doc.Blocks) < 5 {
+			t.Fatalf("期望至少解析出5个块元素，实际为%#v", doc.Blocks)
+		}
+		for i, want := range map[int]string{1: "landscape", 3: "portrait", 4: ""} {
+			if section, ok := doc.Blocks[i].(models.SectionBreak); !ok || section.Orientation != want {
+				t.Errorf("第%d个块元素应为方向为%q的分节符，实际为%#v", i+1, want, doc.Blocks[i])
+			}
+		}
+		if len(doc.Blocks) > 5 {
+			if _, ok := doc.Blocks[5].(models.SectionBreak); ok {
+				t.Error("无法识别的方向不应解析为分节符")
+			}
+		}
+	})
+
+	// 测试案例15：解析分栏块和分栏符
+	t.Run("解析分栏块", func(t *testing.T) {
+		md := "::: columns\n左\n\n\\columnbreak\n右\n:::\n\n::: columns 3\n三栏\n<!-- columnbreak -->\n:::\n\n" +
+			"::: {.columns count=4 gap=8mm}\n四栏\n:::\n\n::: tip\n提示\n:::"
+		doc := ParseMarkdown(md)
+
+		if len(doc.Blocks) != 4 {
+			t.Fatalf("期望解析出4个块元素，实际为%#v", doc.Blocks)
+		}
+		expected := []struct {
+			count  int
+			gap    string
+			blocks []string
+		}{
+			{2, "", []string{"paragraph", "columnbreak", "paragraph"}},
+			{3, "", []string{"paragraph", "columnbreak"}},
+			{4, "8mm", []string{"paragraph"}},
+		}
+		for i, want := range expected {
+			c, ok := doc.Blocks[i].(models.Columns)
+			if !ok {
+				t.Errorf("第%d个块元素应为分栏块，实际为%#v", i+1, doc.Blocks[i])
+				continue
+			}
+			var types []string
+			for _, block := range c.Blocks {
+				types = append(types, block.Type())
+			}
+			if c.Count != want.count || c.Gap != want.gap || !reflect.DeepEqual(types, want.blocks) {
+				t.Errorf("第%d个分栏块解析错误: %d栏，间距%q，内容%v", i+1, c.Count, c.Gap, types)
+			}
+		}
+		if doc.Blocks[3].Type() != "callout" {
+			t.Errorf("其他类型的块仍为提示块，实际为%s", doc.Blocks[3].Type())
+		}
+	})
 }
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"strings"
+
+	"goffice/internal/models"
+)
+
+// parseFrontMatter 解析文档开头由 --- 包围的YAML元数据块，返回元数据和之后的文本行。
+// 与Pandoc一致，开头的 --- 之后是空行或没有结束标记时不视为元数据块；
+// 元数据块无法解析时返回错误和原来的文本行，按分隔线和段落处理
+func parseFrontMatter(lines []string) (models.Metadata, []string, error) {
+	if len(lines) < 2 || strings.TrimRight(lines[0], " \t\r") != "---" || strings.TrimSpace(lines[1]) == "" {
+		return models.Metadata{}, lines, nil
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], " \t\r"); line == "---" || line == "..." {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return models.Metadata{}, lines, nil
+	}
+	fields, err := parseYAML(lines[1:end])
+	if err != nil {
+		return models.Metadata{}, lines, err
+	}
+	return metadataFromFields(fields), lines[end+1:], nil
+}
+
+// metadataFromFields 将YAML字段映射为文档元数据，未识别的字段保存在Extra中
+func metadataFromFields(fields []yamlField) models.Metadata {
+	var meta models.Metadata
+	for _, f := range fields {
+		value := strings.Join(f.values, ", ")
+		switch strings.ToLower(f.key) {
+		case "title":
+			meta.Title = value
+		case "subtitle":
+			meta.Subtitle = value
+		case "author", "authors":
+			meta.Authors = append(meta.Authors, f.values...)
+		case "date":
+			meta.Date = value
+		case "abstract":
+			meta.Abstract = value
+		case "keywords":
+			if f.list {
+				meta.Keywords = append(meta.Keywords, f.values...)
+				break
+			}
+			// 标量形式的关键词以逗号分隔
+			for _, keyword := range strings.Split(value, ",") {
+				if keyword = strings.TrimSpace(keyword); keyword != "" {
+					meta.Keywords = append(meta.Keywords, keyword)
+				}
+			}
+		case "lang", "language":
+			meta.Lang = value
+		default:
+			if meta.Extra == nil {
+				meta.Extra = map[string]string{}
+			}
+			meta.Extra[f.key] = value
+		}
+	}
+	return meta
+}
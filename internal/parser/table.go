@@ -0,0 +1,490 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"goffice/internal/models"
+)
+
+// widePad 标记宽字符在字符网格中占据的第二列
+const widePad = '\x00'
+
+// gridRect 表示网格表格中一个单元格的边框位置（含边框）
+type gridRect struct {
+	top, left, bottom, right int
+}
+
+// parseTable 尝试从第start行开始解析表格，返回表格和表格之后的行号
+func (p *markdownParser) parseTable(lines []string, start int) (models.Table, int, bool) {
+	trimmed := strings.TrimSpace(lines[start])
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		return p.parseGridTable(lines, start)
+	case strings.HasPrefix(trimmed, "-"):
+		return p.parseMultilineTable(lines, start)
+	case strings.Contains(trimmed, "|"):
+		return p.parsePipeTable(lines, start)
+	}
+	return models.Table{}, start, false
+}
+
+// parseGridTable 解析Pandoc风格的网格表格，支持跨行和跨列的单元格
+func (p *markdownParser) parseGridTable(lines []string, start int) (models.Table, int, bool) {
+	var grid [][]rune
+	end := start
+	for end < len(lines) {
+		trimmed := strings.TrimSpace(lines[end])
+		if trimmed == "" || (trimmed[0] != '+' && trimmed[0] != '|') {
+			break
+		}
+		grid = append(grid, displayRunes(trimmed))
+		end++
+	}
+	// 表格必须以边框行结束
+	for len(grid) > 0 && !isGridBorder(grid[len(grid)-1]) {
+		grid = grid[:len(grid)-1]
+		end--
+	}
+	if len(grid) < 3 || !isGridBorder(grid[0]) {
+		return models.Table{}, start, false
+	}
+
+	width := 0
+	for _, row := range grid {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i, row := range grid {
+		for len(row) < width {
+			row = append(row, ' ')
+		}
+		grid[i] = row
+	}
+
+	rects := scanGridCells(grid)
+	if len(rects) == 0 {
+		return models.Table{}, start, false
+	}
+
+	// 表头分隔行使用 "=" 绘制
+	headEnd := -1
+	for i := 1; i < len(grid)-1; i++ {
+		if isGridBorder(grid[i]) && strings.ContainsRune(string(grid[i]), '=') {
+			headEnd = i
+			break
+		}
+	}
+
+	var rowBounds, colBounds []int
+	for _, r := range rects {
+		rowBounds = append(rowBounds, r.top, r.bottom)
+		colBounds = append(colBounds, r.left, r.right)
+	}
+	rowBounds = uniqueSorted(rowBounds)
+	colBounds = uniqueSorted(colBounds)
+
+	var table models.Table
+	alignRow := grid[0]
+	if headEnd > 0 {
+		alignRow = grid[headEnd]
+	}
+	total := float64(colBounds[len(colBounds)-1] - colBounds[0])
+	for i := 0; i+1 < len(colBounds); i++ {
+		seg := alignRow[colBounds[i]+1 : colBounds[i+1]]
+		table.Columns = append(table.Columns, models.TableColumn{
+			Align: alignFromColons(len(seg) > 0 && seg[0] == ':', len(seg) > 0 && seg[len(seg)-1] == ':'),
+			Width: float64(colBounds[i+1]-colBounds[i]) / total,
+		})
+	}
+
+	rows := make([]models.TableRow, len(rowBounds)-1)
+	for _, r := range rects {
+		ri := sort.SearchInts(rowBounds, r.top)
+		var text []string
+		for y := r.top + 1; y < r.bottom; y++ {
+			text = append(text, strings.TrimSpace(gridString(grid[y][r.left+1:r.right])))
+		}
+		rows[ri].Cells = append(rows[ri].Cells, models.TableCell{
+			Blocks:  p.parseText(strings.Join(text, "\n")),
+			ColSpan: sort.SearchInts(colBounds, r.right) - sort.SearchInts(colBounds, r.left),
+			RowSpan: sort.SearchInts(rowBounds, r.bottom) - ri,
+		})
+	}
+	for i, row := range rows {
+		if headEnd > 0 && rowBounds[i] < headEnd {
+			table.Head = append(table.Head, row)
+		} else {
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table, end, true
+}
+
+// scanGridCells 从左上角开始逐个查找网格表格中的单元格
+func scanGridCells(grid [][]rune) []gridRect {
+	height, width := len(grid), len(grid[0])
+	corners := [][2]int{{0, 0}}
+	seen := map[[2]int]bool{{0, 0}: true}
+	var rects []gridRect
+	for len(corners) > 0 {
+		top, left := corners[0][0], corners[0][1]
+		corners = corners[1:]
+		bottom, right, ok := scanGridCell(grid, top, left)
+		if !ok {
+			continue
+		}
+		rects = append(rects, gridRect{top, left, bottom, right})
+		for _, next := range [][2]int{{top, right}, {bottom, left}} {
+			if next[0] < height-1 && next[1] < width-1 && !seen[next] {
+				seen[next] = true
+				corners = append(corners, next)
+			}
+		}
+	}
+	sort.Slice(rects, func(i, j int) bool {
+		if rects[i].top != rects[j].top {
+			return rects[i].top < rects[j].top
+		}
+		return rects[i].left < rects[j].left
+	})
+	return rects
+}
+
+// scanGridCell 查找以(top, left)为左上角的单元格的右下角
+func scanGridCell(grid [][]rune, top, left int) (int, int, bool) {
+	if grid[top][left] != '+' {
+		return 0, 0, false
+	}
+	for right := left + 1; right < len(grid[top]); right++ {
+		switch grid[top][right] {
+		case '+':
+			for bottom := top + 1; bottom < len(grid); bottom++ {
+				ch := grid[bottom][right]
+				if ch == '+' {
+					if gridCellClosed(grid, top, left, bottom, right) {
+						return bottom, right, true
+					}
+				} else if ch != '|' {
+					break
+				}
+			}
+		case '-', '=', ':':
+		default:
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// gridCellClosed 检查单元格的下边框和左边框是否完整
+func gridCellClosed(grid [][]rune, top, left, bottom, right int) bool {
+	if grid[bottom][left] != '+' {
+		return false
+	}
+	for x := left + 1; x < right; x++ {
+		if !strings.ContainsRune("-=:+", grid[bottom][x]) {
+			return false
+		}
+	}
+	for y := top + 1; y < bottom; y++ {
+		if grid[y][left] != '|' && grid[y][left] != '+' {
+			return false
+		}
+	}
+	return true
+}
+
+// isGridBorder 判断是否为网格表格的边框行，如 +---+===+
+func isGridBorder(row []rune) bool {
+	s := strings.TrimRight(string(row), " ")
+	if len(s) < 3 || s[0] != '+' || s[len(s)-1] != '+' {
+		return false
+	}
+	return strings.Trim(s, "+-=:") == ""
+}
+
+// parseMultilineTable 解析Pandoc风格的多行表格
+func (p *markdownParser) parseMultilineTable(lines []string, start int) (models.Table, int, bool) {
+	var header []string
+	i := start + 1
+	first := displayRunes(strings.TrimRight(lines[start], " "))
+	cols := dashGroups(first)
+	if len(cols) == 1 && cols[0][1]-cols[0][0] == len(first) {
+		// 带表头的多行表格以整行的短横线开始，表头后为分列的短横线
+		for ; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				return models.Table{}, start, false
+			}
+			if groups := dashGroups(displayRunes(strings.TrimRight(lines[i], " "))); len(groups) >= 2 {
+				cols = groups
+				i++
+				break
+			}
+			header = append(header, lines[i])
+		}
+		if header == nil {
+			return models.Table{}, start, false
+		}
+	}
+	if len(cols) < 2 {
+		return models.Table{}, start, false
+	}
+
+	var rows [][]string
+	var current []string
+	closed := false
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && strings.Trim(trimmed, "- ") == "" {
+			closed = true
+			i++
+			break
+		}
+		if trimmed == "" {
+			if current != nil {
+				rows = append(rows, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, lines[i])
+	}
+	if !closed || (header == nil && len(rows) == 0 && current == nil) {
+		return models.Table{}, start, false
+	}
+	if current != nil {
+		rows = append(rows, current)
+	}
+
+	// 每列的文本范围从该列起点延伸到下一列起点
+	starts := make([]int, len(cols)+1)
+	for c, g := range cols {
+		starts[c] = g[0]
+	}
+	starts[len(cols)] = cols[len(cols)-1][1]
+	var table models.Table
+	alignLines := header
+	if alignLines == nil && len(rows) > 0 {
+		alignLines = rows[0]
+	}
+	total := float64(starts[len(cols)] - starts[0])
+	for c, g := range cols {
+		flushLeft, flushRight := false, false
+		for _, line := range alignLines {
+			runes := displayRunes(line)
+			if g[0] < len(runes) && runes[g[0]] != ' ' {
+				flushLeft = true
+			}
+			if g[1]-1 < len(runes) && runes[g[1]-1] != ' ' {
+				flushRight = true
+			}
+		}
+		align := "center"
+		switch {
+		case flushLeft && flushRight:
+			align = ""
+		case flushLeft:
+			align = "left"
+		case flushRight:
+			align = "right"
+		}
+		table.Columns = append(table.Columns, models.TableColumn{
+			Align: align,
+			Width: float64(starts[c+1]-starts[c]) / total,
+		})
+	}
+
+	if header != nil {
+		table.Head = []models.TableRow{p.multilineRow(header, starts)}
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, p.multilineRow(row, starts))
+	}
+	return table, i, true
+}
+
+// multilineRow 按列范围切分多行表格中的一行
+func (p *markdownParser) multilineRow(lines []string, starts []int) models.TableRow {
+	var row models.TableRow
+	for c := 0; c+1 < len(starts); c++ {
+		var text []string
+		for _, line := range lines {
+			runes := displayRunes(line)
+			from, to := starts[c], starts[c+1]
+			if c+2 == len(starts) {
+				to = len(runes)
+			}
+			if from >= len(runes) {
+				continue
+			}
+			if to > len(runes) {
+				to = len(runes)
+			}
+			text = append(text, strings.TrimSpace(gridString(runes[from:to])))
+		}
+		row.Cells = append(row.Cells, models.TableCell{
+			Blocks: p.parseText(strings.Join(text, "\n")),
+		})
+	}
+	return row
+}
+
+// dashGroups 返回由空格分隔的短横线段的范围，行内出现其他字符时返回nil
+func dashGroups(row []rune) [][2]int {
+	var groups [][2]int
+	begin := -1
+	for i, r := range row {
+		switch r {
+		case '-':
+			if begin < 0 {
+				begin = i
+			}
+		case ' ':
+			if begin >= 0 {
+				groups = append(groups, [2]int{begin, i})
+				begin = -1
+			}
+		default:
+			return nil
+		}
+	}
+	if begin >= 0 {
+		groups = append(groups, [2]int{begin, len(row)})
+	}
+	for _, g := range groups {
+		if g[1]-g[0] < 3 {
+			return nil
+		}
+	}
+	return groups
+}
+
+// parsePipeTable 解析GFM风格的管道表格
+func (p *markdownParser) parsePipeTable(lines []string, start int) (models.Table, int, bool) {
+	if start+1 >= len(lines) {
+		return models.Table{}, start, false
+	}
+	head := splitPipeRow(lines[start])
+	delims := splitPipeRow(lines[start+1])
+	if len(head) == 0 || len(head) != len(delims) {
+		return models.Table{}, start, false
+	}
+	var table models.Table
+	for _, d := range delims {
+		d = strings.TrimSpace(d)
+		if strings.Trim(d, ":") == "" || strings.Trim(d, "-:") != "" || strings.Contains(strings.Trim(d, ":"), ":") {
+			return models.Table{}, start, false
+		}
+		table.Columns = append(table.Columns, models.TableColumn{
+			Align: alignFromColons(strings.HasPrefix(d, ":"), strings.HasSuffix(d, ":")),
+		})
+	}
+
+	table.Head = []models.TableRow{p.pipeRow(head, len(head))}
+	i := start + 2
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || !strings.Contains(trimmed, "|") {
+			break
+		}
+		table.Rows = append(table.Rows, p.pipeRow(splitPipeRow(trimmed), len(head)))
+	}
+	return table, i, true
+}
+
+// splitPipeRow 将管道表格的一行拆分为单元格文本，支持 \| 转义
+func splitPipeRow(line string) []string {
+	line = strings.TrimSpace(line)
+	if !strings.Contains(line, "|") {
+		return nil
+	}
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+		} else if line[i] == '|' {
+			cells = append(cells, cell.String())
+			cell.Reset()
+		} else {
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, cell.String())
+}
+
+// pipeRow 将单元格文本转换为表格行，并补齐或截断到指定列数
+func (p *markdownParser) pipeRow(texts []string, cols int) models.TableRow {
+	var row models.TableRow
+	for c := 0; c < cols; c++ {
+		var cell models.TableCell
+		if c < len(texts) {
+			cell.Blocks = p.parseText(strings.TrimSpace(texts[c]))
+		}
+		row.Cells = append(row.Cells, cell)
+	}
+	return row
+}
+
+// alignFromColons 根据分隔线两端的冒号确定对齐方式
+func alignFromColons(left, right bool) string {
+	switch {
+	case left && right:
+		return "center"
+	case left:
+		return "left"
+	case right:
+		return "right"
+	}
+	return ""
+}
+
+// displayRunes 将文本展开为按显示宽度排列的字符网格，宽字符占两列
+func displayRunes(s string) []rune {
+	var runes []rune
+	for _, r := range s {
+		runes = append(runes, r)
+		if isWide(r) {
+			runes = append(runes, widePad)
+		}
+	}
+	return runes
+}
+
+// gridString 将字符网格还原为文本
+func gridString(runes []rune) string {
+	var b strings.Builder
+	for _, r := range runes {
+		if r != widePad {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// isWide 判断字符在等宽字体下是否占两列（中日韩文字及全角符号）
+func isWide(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF01 && r <= 0xFF60) || (r >= 0xFFE0 && r <= 0xFFE6)
+}
+
+// uniqueSorted 对整数去重并排序
+func uniqueSorted(values []int) []int {
+	sort.Ints(values)
+	var result []int
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+// cellText 返回单元格中第一个段落的纯文本
+func cellText(cell models.TableCell) string {
+	if len(cell.Blocks) == 0 {
+		return ""
+	}
+	if p, ok := cell.Blocks[0].(models.Paragraph); ok && len(p.Inlines) > 0 {
+		if text, ok := p.Inlines[0].(models.Text); ok {
+			return text.Content
+		}
+	}
+	return ""
+}
+
+func TestParseTable(t *testing.T) {
+	t.Run("解析网格表格的跨行跨列", func(t *testing.T) {
+		md := "+--------+-------+-------+\n" +
+			"| 名称   | 数量  | 备注  |\n" +
+			"+:=======+=======+======:+\n" +
+			"| a      | 合并两列      |\n" +
+			"+--------+-------+-------+\n" +
+			"| 跨行   | x     | y     |\n" +
+			"|        +-------+-------+\n" +
+			"|        | z     | w     |\n" +
+			"+--------+-------+-------+"
+		doc := ParseMarkdown(md)
+		if len(doc.Blocks) != 1 {
+			t.Fatalf("期望解析出1个块元素，实际为%d", len(doc.Blocks))
+		}
+		table, ok := doc.Blocks[0].(models.Table)
+		if !ok {
+			t.Fatalf("块元素应为Table类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if len(table.Columns) != 3 || len(table.Head) != 1 || len(table.Rows) != 3 {
+			t.Fatalf("表格结构错误，期望3列1行表头3行表体，实际为%d列%d行表头%d行表体", len(table.Columns), len(table.Head), len(table.Rows))
+		}
+		if table.Columns[0].Align != "left" || table.Columns[1].Align != "" || table.Columns[2].Align != "right" {
+			t.Errorf("列对齐方式解析错误: %+v", table.Columns)
+		}
+		if w := table.Columns[0].Width; w < 0.34 || w > 0.36 {
+			t.Errorf("第一列相对宽度应约为0.35，实际为%f", w)
+		}
+		if got := cellText(table.Head[0].Cells[0]); got != "名称" {
+			t.Errorf("表头文本解析错误，期望为'名称'，实际为'%s'", got)
+		}
+		if cell := table.Rows[0].Cells[1]; cell.ColSpan != 2 || cellText(cell) != "合并两列" {
+			t.Errorf("跨列单元格解析错误: ColSpan=%d, 文本='%s'", cell.ColSpan, cellText(cell))
+		}
+		if cell := table.Rows[1].Cells[0]; cell.RowSpan != 2 || cellText(cell) != "跨行" {
+			t.Errorf("跨行单元格解析错误: RowSpan=%d, 文本='%s'", cell.RowSpan, cellText(cell))
+		}
+		if len(table.Rows[2].Cells) != 2 {
+			t.Errorf("被跨行占据的行应只包含2个单元格，实际为%d", len(table.Rows[2].Cells))
+		}
+	})
+
+	t.Run("解析多行表格", func(t *testing.T) {
+		md := "-------------------------------------\n" +
+			"   居中   Default   右对齐 左对齐\n" +
+			"--------- ------- -------- ---------\n" +
+			"  第一行   a           1.0 跨越多行的\n" +
+			"                           单元格\n" +
+			"\n" +
+			"  第二行   b           2.0 c\n" +
+			"-------------------------------------"
+		doc := ParseMarkdown(md)
+		if len(doc.Blocks) != 1 {
+			t.Fatalf("期望解析出1个块元素，实际为%d", len(doc.Blocks))
+		}
+		table, ok := doc.Blocks[0].(models.Table)
+		if !ok {
+			t.Fatalf("块元素应为Table类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if len(table.Head) != 1 || len(table.Rows) != 2 {
+			t.Fatalf("期望1行表头2行表体，实际为%d行表头%d行表体", len(table.Head), len(table.Rows))
+		}
+		aligns := []string{"center", "", "right", "left"}
+		for i, col := range table.Columns {
+			if col.Align != aligns[i] {
+				t.Errorf("第%d列对齐方式应为'%s'，实际为'%s'", i+1, aligns[i], col.Align)
+			}
+		}
+		if got := cellText(table.Rows[0].Cells[3]); got != "跨越多行的 单元格" {
+			t.Errorf("多行单元格解析错误，实际为'%s'", got)
+		}
+	})
+
+	t.Run("解析管道表格", func(t *testing.T) {
+		md := "| a | b |\n|:--|--:|\n| 1 | 2 \\| 3 |\n| 4 |"
+		doc := ParseMarkdown(md)
+		table, ok := doc.Blocks[0].(models.Table)
+		if !ok {
+			t.Fatalf("块元素应为Table类型，实际为%s", reflect.TypeOf(doc.Blocks[0]))
+		}
+		if table.Columns[0].Align != "left" || table.Columns[1].Align != "right" {
+			t.Errorf("列对齐方式解析错误: %+v", table.Columns)
+		}
+		if got := cellText(table.Rows[0].Cells[1]); got != "2 | 3" {
+			t.Errorf("转义的竖线解析错误，实际为'%s'", got)
+		}
+		if len(table.Rows[1].Cells) != 2 {
+			t.Errorf("缺少的单元格应被补齐，实际为%d个", len(table.Rows[1].Cells))
+		}
+	})
+
+	t.Run("分隔线不是表格", func(t *testing.T) {
+		doc := ParseMarkdown("---\n文本\n---")
+		for _, block := range doc.Blocks {
+			if _, ok := block.(models.Table); ok {
+				t.Error("单独的短横线不应被解析为表格")
+			}
+		}
+	})
+}
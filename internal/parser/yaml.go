@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlField 表示YAML映射中的一个顶层字段
+type yamlField struct {
+	key    string
+	values []string // 标量字段只有一个值
+	list   bool     // 是否为列表
+}
+
+// parseYAML 解析YAML的一个子集：顶层的 key: value 映射，值可以是普通、带引号或块标量（| 和 >），
+// 以及 [a, b] 和 "- a" 形式的列表。不支持嵌套映射、锚点和多文档
+func parseYAML(lines []string) ([]yamlField, error) {
+	var fields []yamlField
+	for i := 0; i < len(lines); i++ {
+		line := stripYAMLComment(lines[i])
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return nil, fmt.Errorf("第%d行: 不支持的缩进或嵌套", i+1)
+		}
+		colon := yamlKeyEnd(line)
+		if colon == -1 {
+			return nil, fmt.Errorf("第%d行: 缺少 \"键: 值\" 中的冒号", i+1)
+		}
+		field := yamlField{key: unquoteYAML(strings.TrimSpace(line[:colon]))}
+		value := strings.TrimSpace(line[colon+1:])
+
+		// 收集之后缩进的行或列表项
+		var block []string
+		for i+1 < len(lines) {
+			next := lines[i+1]
+			trimmed := strings.TrimSpace(next)
+			if trimmed != "" && !strings.HasPrefix(next, " ") && !strings.HasPrefix(next, "\t") && !strings.HasPrefix(next, "- ") && trimmed != "-" {
+				break
+			}
+			block = append(block, next)
+			i++
+		}
+		for len(block) > 0 && strings.TrimSpace(block[len(block)-1]) == "" {
+			block = block[:len(block)-1]
+		}
+
+		switch {
+		case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			field.values = []string{yamlBlockScalar(block, value[0] == '>')}
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") || len(block) > 0 {
+				return nil, fmt.Errorf("第%d行: 列表缺少结束的 ]", i+1)
+			}
+			field.list = true
+			for _, item := range splitYAMLFlow(value[1 : len(value)-1]) {
+				field.values = append(field.values, unquoteYAML(item))
+			}
+		case value == "" && len(block) > 0:
+			field.list = true
+			for _, item := range block {
+				item = strings.TrimSpace(stripYAMLComment(item))
+				if item == "" {
+					continue
+				}
+				if item != "-" && !strings.HasPrefix(item, "- ") {
+					return nil, fmt.Errorf("字段 %s: 不支持嵌套的映射", field.key)
+				}
+				field.values = append(field.values, unquoteYAML(strings.TrimSpace(item[1:])))
+			}
+		default:
+			// 普通标量可以折行书写
+			for _, item := range block {
+				if item = strings.TrimSpace(stripYAMLComment(item)); item != "" {
+					value += " " + item
+				}
+			}
+			field.values = []string{unquoteYAML(value)}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// yamlKeyEnd 返回键之后冒号的位置，冒号之后必须是空白或行尾
+func yamlKeyEnd(line string) int {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t'):
+			return i
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment 去掉引号之外以空白和 # 开始的注释
+func stripYAMLComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+// yamlBlockScalar 合并块标量的各行：| 保留换行，> 将相邻行折叠为空格，空行作为段落分隔
+func yamlBlockScalar(lines []string, folded bool) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			n := len(line) - len(strings.TrimLeft(line, " \t"))
+			if indent == -1 || n < indent {
+				indent = n
+			}
+		}
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		} else {
+			line = strings.TrimSpace(line)
+		}
+		if i > 0 {
+			if folded && line != "" && strings.TrimSpace(lines[i-1]) != "" {
+				b.WriteString(" ")
+			} else {
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// splitYAMLFlow 按引号之外的逗号拆分流式列表的内容
+func splitYAMLFlow(text string) []string {
+	var items []string
+	quote := byte(0)
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	return items
+}
+
+// unquoteYAML 去掉标量两端的引号，双引号中的转义按Go的规则处理，单引号中的 ” 表示一个单引号
+func unquoteYAML(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+		return value[1 : len(value)-1]
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
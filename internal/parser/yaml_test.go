@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"goffice/internal/models"
+)
+
+func TestParseYAML(t *testing.T) {
+	t.Run("标量和列表", func(t *testing.T) {
+		fields, err := parseYAML([]string{
+			`title: "Go 语言：入门 # 不是注释"`,
+			`subtitle: 'It''s easy' # 注释`,
+			"author:",
+			"  - 张三",
+			"  - \"李四\"",
+			"keywords: [Go, 'Markdown, DOCX']",
+			"abstract: >",
+			"  第一行",
+			"  第二行",
+			"",
+			"  第二段",
+			"note: |",
+			"  a",
+			"  b",
+			"date: 2024-05-01",
+			"long: 折行",
+			"  书写",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []yamlField{
+			{key: "title", values: []string{"Go 语言：入门 # 不是注释"}},
+			{key: "subtitle", values: []string{"It's easy"}},
+			{key: "author", values: []string{"张三", "李四"}, list: true},
+			{key: "keywords", values: []string{"Go", "Markdown, DOCX"}, list: true},
+			{key: "abstract", values: []string{"第一行 第二行\n\n第二段"}},
+			{key: "note", values: []string{"a\nb"}},
+			{key: "date", values: []string{"2024-05-01"}},
+			{key: "long", values: []string{"折行 书写"}},
+		}
+		if !reflect.DeepEqual(fields, expected) {
+			t.Errorf("解析结果错误:\n期望 %#v\n实际 %#v", expected, fields)
+		}
+	})
+
+	t.Run("不支持的语法", func(t *testing.T) {
+		for _, lines := range [][]string{
+			{"没有冒号"},
+			{"  缩进: 1"},
+			{"map:", "  key: value"},
+			{"list: [a, b"},
+		} {
+			if _, err := parseYAML(lines); err == nil {
+				t.Errorf("%q 应返回错误", lines)
+			}
+		}
+	})
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	doc := ParseMarkdown("---\ntitle: 报告\nauthor: 王五\nkeywords: 甲, 乙\nlang: zh-CN\ncolumns: 2\n---\n# 正文")
+	meta := doc.Metadata
+	if meta.Title != "报告" || !reflect.DeepEqual(meta.Authors, []string{"王五"}) || meta.Lang != "zh-CN" {
+		t.Errorf("元数据解析错误: %#v", meta)
+	}
+	if !reflect.DeepEqual(meta.Keywords, []string{"甲", "乙"}) {
+		t.Errorf("逗号分隔的关键词应拆分，实际为%q", meta.Keywords)
+	}
+	if meta.Extra["columns"] != "2" {
+		t.Errorf("未识别的字段应保存在Extra中，实际为%#v", meta.Extra)
+	}
+	if len(doc.Blocks) != 1 || doc.Blocks[0].Type() != "header" {
+		t.Errorf("元数据块应从正文中移除，实际为%#v", doc.Blocks)
+	}
+
+	doc = ParseMarkdown("---\n没有结束标记")
+	if !reflect.DeepEqual(doc.Metadata, models.Metadata{}) || len(doc.Blocks) != 2 {
+		t.Errorf("没有结束标记时不应作为元数据，实际为%#v", doc)
+	}
+
+	// 开头的 --- 之后是空行时为分隔线
+	doc = ParseMarkdown("---\n\nIntro paragraph\n\n---\n\nMore")
+	var types []string
+	for _, block := range doc.Blocks {
+		types = append(types, block.Type())
+	}
+	if !reflect.DeepEqual(doc.Metadata, models.Metadata{}) || !reflect.DeepEqual(types, []string{"horizontalrule", "paragraph", "horizontalrule", "paragraph"}) {
+		t.Errorf("--- 之后是空行时不应作为元数据，实际为%#v", doc)
+	}
+
+	// 无法解析的元数据块给出警告并按正文处理
+	doc, warnings := ParseMarkdownWithWarnings("---\ntitle: [未结束\n---\n\n正文")
+	if len(warnings) != 1 || warnings[0] != "解析YAML元数据失败，按正文处理: 第1行: 列表缺少结束的 ]" {
+		t.Errorf("无法解析的元数据应给出警告，实际为%q", warnings)
+	}
+	if !reflect.DeepEqual(doc.Metadata, models.Metadata{}) || len(doc.Blocks) != 3 || doc.Blocks[0].Type() != "horizontalrule" {
+		t.Errorf("无法解析的元数据块应保留为正文，实际为%#v", doc.Blocks)
+	}
+}
@@ -1,6 +1,8 @@
 package latex
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"strings"
 )
@@ -139,20 +141,20 @@ func ToOMML(latex string) string {
 			if denEnd > 0 {
 				den := denRest[:denEnd]
 				fmt.Printf("分子: %s, 分母: %s\n", num, den)
-				result = `<m:f><m:fPr><m:type m:val="bar"/></m:fPr><m:num><m:r><m:t>` + num + `</m:t></m:r></m:num><m:den><m:r><m:t>` + den + `</m:t></m:r></m:den></m:f>`
+				result = `<m:f><m:fPr><m:type m:val="bar"/></m:fPr><m:num><m:r><m:t>` + escapeText(num) + `</m:t></m:r></m:num><m:den><m:r><m:t>` + escapeText(den) + `</m:t></m:r></m:den></m:f>`
 			} else {
 				fmt.Println("分数公式格式不正确 - 无法解析分母")
-				result = `<m:r><m:t>` + latex + `</m:t></m:r>`
+				result = `<m:r><m:t>` + escapeText(latex) + `</m:t></m:r>`
 			}
 		} else {
 			fmt.Println("分数公式格式不正确 - 无法解析分子")
-			result = `<m:r><m:t>` + latex + `</m:t></m:r>`
+			result = `<m:r><m:t>` + escapeText(latex) + `</m:t></m:r>`
 		}
 	} else if strings.Contains(latex, "=") && strings.Contains(latex, "^") {
 		// 处理 E=mc^2 这样的公式
 		fmt.Println("检测到带等号和指数的公式")
 		equation := latex
-		result = `<m:r><m:t>` + equation + `</m:t></m:r>`
+		result = `<m:r><m:t>` + escapeText(equation) + `</m:t></m:r>`
 	} else if strings.Contains(latex, "^") {
 		fmt.Println("检测到上标公式")
 
@@ -163,23 +165,23 @@ func ToOMML(latex string) string {
 				base := parts[0]
 				exp := parts[1][:strings.Index(parts[1], "}")]
 				fmt.Printf("基数: %s, 花括号包裹的指数: %s\n", base, exp)
-				result = `<m:sSup><m:e><m:r><m:t>` + base + `</m:t></m:r></m:e><m:sup><m:r><m:t>` + exp + `</m:t></m:r></m:sup></m:sSup>`
+				result = `<m:sSup><m:e><m:r><m:t>` + escapeText(base) + `</m:t></m:r></m:e><m:sup><m:r><m:t>` + escapeText(exp) + `</m:t></m:r></m:sup></m:sSup>`
 
 				// 如果在指数后还有其他内容
 				if len(parts[1]) > strings.Index(parts[1], "}")+1 {
 					suffix := parts[1][strings.Index(parts[1], "}")+1:]
 					fmt.Printf("指数后的其他内容: %s\n", suffix)
-					result += `<m:r><m:t>` + suffix + `</m:t></m:r>`
+					result += `<m:r><m:t>` + escapeText(suffix) + `</m:t></m:r>`
 				}
 			} else {
-				result = `<m:r><m:t>` + latex + `</m:t></m:r>`
+				result = `<m:r><m:t>` + escapeText(latex) + `</m:t></m:r>`
 			}
 		} else {
 			// 简单情况：变量^指数
 			base := latex[:strings.Index(latex, "^")]
 			exp := latex[strings.Index(latex, "^")+1:]
 			fmt.Printf("基数: %s, 简单指数: %s\n", base, exp)
-			result = `<m:sSup><m:e><m:r><m:t>` + base + `</m:t></m:r></m:e><m:sup><m:r><m:t>` + exp + `</m:t></m:r></m:sup></m:sSup>`
+			result = `<m:sSup><m:e><m:r><m:t>` + escapeText(base) + `</m:t></m:r></m:e><m:sup><m:r><m:t>` + escapeText(exp) + `</m:t></m:r></m:sup></m:sSup>`
 		}
 	} else if strings.Contains(latex, "_") {
 		fmt.Println("检测到下标公式")
@@ -189,28 +191,40 @@ func ToOMML(latex string) string {
 				base := parts[0]
 				sub := parts[1][:strings.Index(parts[1], "}")]
 				fmt.Printf("基数: %s, 花括号包裹的下标: %s\n", base, sub)
-				result = `<m:sSub><m:e><m:r><m:t>` + base + `</m:t></m:r></m:e><m:sub><m:r><m:t>` + sub + `</m:t></m:r></m:sub></m:sSub>`
+				result = `<m:sSub><m:e><m:r><m:t>` + escapeText(base) + `</m:t></m:r></m:e><m:sub><m:r><m:t>` + escapeText(sub) + `</m:t></m:r></m:sub></m:sSub>`
 
 				// 如果在下标后还有其他内容
 				if len(parts[1]) > strings.Index(parts[1], "}")+1 {
 					suffix := parts[1][strings.Index(parts[1], "}")+1:]
 					fmt.Printf("下标后的其他内容: %s\n", suffix)
-					result += `<m:r><m:t>` + suffix + `</m:t></m:r>`
+					result += `<m:r><m:t>` + escapeText(suffix) + `</m:t></m:r>`
 				}
 			} else {
-				result = `<m:r><m:t>` + latex + `</m:t></m:r>`
+				result = `<m:r><m:t>` + escapeText(latex) + `</m:t></m:r>`
 			}
 		} else {
 			// 简单情况
 			base := latex[:strings.Index(latex, "_")]
 			sub := latex[strings.Index(latex, "_")+1:]
 			fmt.Printf("基数: %s, 简单下标: %s\n", base, sub)
-			result = `<m:sSub><m:e><m:r><m:t>` + base + `</m:t></m:r></m:e><m:sub><m:r><m:t>` + sub + `</m:t></m:r></m:sub></m:sSub>`
+			result = `<m:sSub><m:e><m:r><m:t>` + escapeText(base) + `</m:t></m:r></m:e><m:sub><m:r><m:t>` + escapeText(sub) + `</m:t></m:r></m:sub></m:sSub>`
 		}
 	} else {
 		// 其他简单公式
-		result = `<m:r><m:t>` + latex + `</m:t></m:r>`
+		result = `<m:r><m:t>` + escapeText(latex) + `</m:t></m:r>`
 	}
 
 	return result
 }
+
+// escapeText 转义写入 <m:t> 的文本，并去掉XML 1.0中不允许出现的字符
+func escapeText(text string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || r >= 0x20 && r <= 0xD7FF || r >= 0xE000 && r <= 0xFFFD || r >= 0x10000 {
+			return r
+		}
+		return -1
+	}, text)))
+	return b.String()
+}
@@ -56,6 +56,21 @@ func TestToOMML(t *testing.T) {
 			latex:      "\\nabla \\times \\vec{E}",
 			expectPart: "<m:r><m:t>∇</m:t></m:r><m:r><m:t>×</m:t></m:r>", // 验证处理了向量算符
 		},
+		{
+			name:       "转义特殊字符",
+			latex:      "a < b \\& c",
+			expectPart: "<m:r><m:t>a &lt; b \\&amp; c</m:t></m:r>",
+		},
+		{
+			name:       "分数中的特殊字符",
+			latex:      "\\frac{a<b}{c>d}",
+			expectPart: "<m:num><m:r><m:t>a&lt;b</m:t></m:r></m:num><m:den><m:r><m:t>c&gt;d</m:t></m:r></m:den>",
+		},
+		{
+			name:       "不完整的left",
+			latex:      "\\left\\right",
+			expectPart: "\\right</m:t>", // 不应越界
+		},
 	}
 
 	for _, tc := range testCases {
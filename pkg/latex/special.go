@@ -19,6 +19,10 @@ func processLeftRight(latex string) string {
 			break
 		}
 		rightIdx += leftIdx
+		if rightIdx < leftIdx+6 || rightIdx+6 >= len(result) {
+			// 不完整的 \left 或 \right，保留原文
+			break
+		}
 
 		// 替换为简单括号
 		leftChar := string(result[leftIdx+5])